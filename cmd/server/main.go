@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -13,6 +17,7 @@ import (
 	"github.com/tmunongo/nanotools/internal/db"
 	"github.com/tmunongo/nanotools/internal/handlers"
 	custommw "github.com/tmunongo/nanotools/internal/middleware"
+	"github.com/tmunongo/nanotools/internal/services"
 )
 
 func main() {
@@ -48,6 +53,8 @@ func main() {
 
 	videoDownloadLimiter := custommw.NewRateLimiter(1.0, 3)
 
+	playlistJobs := services.NewPlaylistJobManager()
+
 	// routes
 	fileServer := http.FileServer(http.Dir("web/static"))
 	r.Handle("/static/*", http.StripPrefix("/static", fileServer))
@@ -73,14 +80,63 @@ func main() {
 
 	r.Get("/tools/image-converter", handlers.ImageConverterPageHandler)
 	r.Post("/api/tools/image/convert", handlers.ImageConvertHandler(queries))
+	r.Post("/api/tools/image/convert-batch", handlers.ImageConvertBatchHandler(queries))
+	r.Post("/api/tools/image/metadata", handlers.ImageMetadataHandler(queries))
+	r.Post("/api/tools/image/strip", handlers.ImageStripHandler(queries))
+
+	r.Get("/tools/image-compressor", handlers.ImageCompressorPageHandler)
+	r.Post("/api/tools/image/compress", handlers.ImageCompressHandler(queries))
+
+	r.Get("/tools/favicon-generator", handlers.FaviconGeneratorPageHandler)
+	r.Post("/api/tools/image/favicon", handlers.FaviconGenerateHandler(queries))
+
+	r.Get("/tools/responsive-images", handlers.ResponsiveImagesPageHandler)
+	r.Post("/api/tools/image/responsive", handlers.ResponsiveImagesHandler(queries))
+
+	r.Get("/tools/image-diff", handlers.ImageDiffPageHandler)
+	r.Post("/api/tools/image/diff", handlers.ImageDiffHandler(queries))
+
+	r.Get("/tools/color-palette", handlers.ColorPalettePageHandler)
+	r.Post("/api/tools/image/palette", handlers.ImageAnalyzeHandler(queries))
+
+	r.Get("/tools/svg-tools", handlers.SVGToolsPageHandler)
+	r.Post("/api/tools/svg/optimize", handlers.SVGOptimizeHandler(queries))
+	r.Post("/api/tools/svg/rasterize", handlers.SVGRasterizeHandler(queries))
 
 	r.Get("/tools/video-downloader", handlers.VideoDownloaderPageHandler)
 	r.Post("/api/tools/video/info", handlers.VideoInfoHandler(queries))
 	r.With(videoDownloadLimiter.Middleware).Post("/api/tools/video/download", handlers.VideoDownloadHandler(queries))
+	r.With(videoDownloadLimiter.Middleware).Post("/api/tools/video/subtitles", handlers.VideoSubtitlesHandler(queries))
+
+	r.Get("/tools/playlist-downloader", handlers.PlaylistDownloaderPageHandler)
+	r.Post("/api/tools/video/playlist/info", handlers.PlaylistInfoHandler(queries))
+	r.With(videoDownloadLimiter.Middleware).Post("/api/tools/video/playlist/jobs", handlers.PlaylistJobHandler(queries, playlistJobs))
+	r.Get("/api/tools/video/playlist/jobs/{id}", handlers.PlaylistJobStatusHandler(playlistJobs))
+	r.Delete("/api/tools/video/playlist/jobs/{id}", handlers.PlaylistJobCancelHandler(playlistJobs))
+	r.Get("/api/tools/video/playlist/jobs/{id}/files/{index}", handlers.PlaylistFileHandler(playlistJobs))
+	r.Get("/api/tools/video/playlist/jobs/{id}/archive", handlers.PlaylistArchiveHandler(queries, playlistJobs))
+
+	r.Get("/tools/video-to-gif", handlers.VideoToGIFPageHandler)
+	r.With(videoDownloadLimiter.Middleware).Post("/api/tools/video/gif", handlers.VideoToGIFHandler(queries))
 
 	r.Get("/tools/pdf-to-images", handlers.PDFConverterPageHandler)
 	r.Post("/api/tools/pdf/to-images", handlers.PDFToImagesHandler(queries))
 
+	r.Get("/tools/pdf-organizer", handlers.PDFOrganizerPageHandler)
+	r.Post("/api/tools/pdf/merge", handlers.PDFMergeHandler(queries))
+	r.Post("/api/tools/pdf/split", handlers.PDFSplitHandler(queries))
+	r.Post("/api/tools/pdf/rotate", handlers.PDFRotateHandler(queries))
+	r.Post("/api/tools/pdf/reorder", handlers.PDFReorderHandler(queries))
+
+	r.Get("/tools/pdf-compressor", handlers.PDFCompressorPageHandler)
+	r.Post("/api/tools/pdf/compress", handlers.PDFCompressHandler(queries))
+
+	r.Get("/tools/images-to-pdf", handlers.ImagesToPDFPageHandler)
+	r.Post("/api/tools/pdf/from-images", handlers.ImagesToPDFHandler(queries))
+
+	r.Get("/tools/pdf-to-text", handlers.PDFTextPageHandler)
+	r.Post("/api/tools/pdf/to-text", handlers.PDFTextHandler(queries))
+
 	// Health check endpoint
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -97,8 +153,32 @@ func main() {
 	addr := fmt.Sprintf(":%s", port)
 	fmt.Printf("Starting nanotools on %s\n", addr)
 
-	if err := http.ListenAndServe(addr, r); err != nil {
+	srv := &http.Server{Addr: addr, Handler: r}
+
+	// Stop accepting requests on SIGINT/SIGTERM so background playlist jobs
+	// can be cancelled and their files removed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// ListenAndServe returns as soon as Shutdown starts, so drained is
+	// waited on before job files are removed from under streaming responses
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			// connections still open after the timeout are cut off
+			srv.Close()
+		}
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		fmt.Fprintf(os.Stderr, "Server failed to start: %v\n", err)
 		os.Exit(1)
 	}
+
+	<-drained
+	playlistJobs.Shutdown()
 }
@@ -3,12 +3,20 @@ module github.com/tmunongo/nanotools
 go 1.25.4
 
 require (
-	github.com/a-h/templ v0.3.960 // indirect
-	github.com/chai2010/webp v1.4.0 // indirect
-	github.com/go-chi/chi/v5 v5.2.3 // indirect
-	github.com/google/uuid v1.6.0 // indirect
-	github.com/gosimple/slug v1.15.0 // indirect
+	github.com/a-h/templ v0.3.960
+	github.com/chai2010/webp v1.4.0
+	github.com/go-chi/chi/v5 v5.2.3
+	github.com/google/uuid v1.6.0
+	github.com/gosimple/slug v1.15.0
+	github.com/mattn/go-sqlite3 v1.14.32
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c
+	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef
+	golang.org/x/image v0.25.0
+)
+
+require (
 	github.com/gosimple/unidecode v1.0.1 // indirect
-	github.com/mattn/go-sqlite3 v1.14.32 // indirect
-	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e // indirect
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
 )
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+)
+
+// logToolUsage records a tool invocation in the audit log. A nil err is
+// logged as a success; sizes of zero or less are left empty.
+func logToolUsage(r *http.Request, queries *db.Queries, toolName string, inputSize, outputSize int64, startTime time.Time, err error) {
+	params := db.CreateAuditLogParams{
+		ToolName:         toolName,
+		IpAddress:        r.RemoteAddr,
+		UserAgent:        sql.NullString{String: r.UserAgent(), Valid: true},
+		InputSizeBytes:   sql.NullInt64{Int64: inputSize, Valid: inputSize > 0},
+		OutputSizeBytes:  sql.NullInt64{Int64: outputSize, Valid: outputSize > 0},
+		ProcessingTimeMs: sql.NullInt64{Int64: time.Since(startTime).Milliseconds(), Valid: true},
+		Status:           "success",
+	}
+
+	if err != nil {
+		params.Status = "error"
+		params.ErrorMessage = sql.NullString{String: err.Error(), Valid: true}
+	}
+
+	_, _ = queries.CreateAuditLog(r.Context(), params)
+}
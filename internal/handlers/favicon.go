@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func FaviconGeneratorPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.FaviconGeneratorPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// FaviconGenerateHandler returns the icon set as a base64 ZIP alongside the
+// HTML snippet and previews, so the page can show them before downloading
+func FaviconGenerateHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 10MB limit
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			http.Error(w, "No image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		padding, _ := strconv.Atoi(r.FormValue("maskable_padding"))
+
+		set, err := services.GenerateFaviconSet(file, services.FaviconOptions{
+			Name:            r.FormValue("name"),
+			ShortName:       r.FormValue("short_name"),
+			ThemeColor:      parseColor(r.FormValue("theme_color")),
+			BackgroundColor: parseColor(r.FormValue("background_color")),
+			MaskablePadding: padding,
+		})
+		if err != nil {
+			logToolUsage(r, queries, "favicon_generator", header.Size, 0, startTime, err)
+			writeImageError(w, "Failed to generate icons", http.StatusInternalServerError, err)
+			return
+		}
+
+		var archive bytes.Buffer
+		if err := services.WriteZip(&archive, set.Files); err != nil {
+			logToolUsage(r, queries, "favicon_generator", header.Size, 0, startTime, err)
+			http.Error(w, "Failed to create archive", http.StatusInternalServerError)
+			return
+		}
+
+		logToolUsage(r, queries, "favicon_generator", header.Size, int64(archive.Len()), startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":  true,
+			"snippet":  set.Snippet,
+			"manifest": string(set.File("site.webmanifest")),
+			"preview":  set.File("android-chrome-192x192.png"),
+			"maskable": set.File("maskable-icon-512x512.png"),
+			"archive":  archive.Bytes(),
+		})
+	}
+}
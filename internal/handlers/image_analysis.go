@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func ColorPalettePageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.ColorPalettePage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// ImageAnalyzeHandler returns the palette and analysis of "image" as JSON.
+// The "output" field selects just the CSS custom properties ("css") or the
+// Tailwind config ("tailwind") instead.
+func ImageAnalyzeHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 10MB limit
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			http.Error(w, "No image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		colors := 0
+		if c := r.FormValue("colors"); c != "" {
+			if colors, err = strconv.Atoi(c); err != nil {
+				http.Error(w, "Invalid number of colours", http.StatusBadRequest)
+				return
+			}
+		}
+
+		analysis, err := services.AnalyzeImage(file, services.ImageAnalysisOptions{
+			Colors: colors,
+			Method: r.FormValue("method"),
+		})
+		if err != nil {
+			logToolUsage(r, queries, "image_palette", header.Size, 0, startTime, err)
+			writeImageError(w, "Failed to analyze image", http.StatusBadRequest, err)
+			return
+		}
+
+		switch r.FormValue("output") {
+		case "css":
+			logToolUsage(r, queries, "image_palette", header.Size, int64(len(analysis.CSS)), startTime, nil)
+			w.Header().Set("Content-Type", "text/css; charset=utf-8")
+			w.Write([]byte(analysis.CSS))
+
+		case "tailwind":
+			logToolUsage(r, queries, "image_palette", header.Size, int64(len(analysis.Tailwind)), startTime, nil)
+			w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+			w.Write([]byte(analysis.Tailwind))
+
+		default:
+			logToolUsage(r, queries, "image_palette", header.Size, 0, startTime, nil)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"success":  true,
+				"analysis": analysis,
+			})
+		}
+	}
+}
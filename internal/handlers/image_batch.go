@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+)
+
+// imageBatchReport is written to report.json at the end of a batch archive
+type imageBatchReport struct {
+	Converted int                         `json:"converted"`
+	Failed    int                         `json:"failed"`
+	Files     []services.BatchImageResult `json:"files"`
+}
+
+// ImageConvertBatchHandler converts every uploaded "images" file, expanding
+// ZIP uploads, with the same options as ImageConvertHandler. The response is
+// a ZIP streamed as files finish, ending with report.json which lists the
+// outcome of every file.
+func ImageConvertBatchHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 50MB limit, the most the server accepts
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Uploads are limited to 50MB in total", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Files too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		headers := r.MultipartForm.File["images"]
+		if len(headers) == 0 {
+			http.Error(w, "No images uploaded", http.StatusBadRequest)
+			return
+		}
+
+		opts, err := parseImageConvertOptions(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if opts.OutputFormat == "jpg" {
+			opts.OutputFormat = "jpeg"
+		}
+		if !slices.Contains(services.ImageFormats, opts.OutputFormat) {
+			http.Error(w, fmt.Sprintf("Unsupported output format: %s", opts.OutputFormat), http.StatusBadRequest)
+			return
+		}
+
+		var inputSize int64
+		uploads := make([]services.BatchImageInput, 0, len(headers))
+		for _, header := range headers {
+			file, err := header.Open()
+			if err != nil {
+				http.Error(w, "Failed to read uploaded image", http.StatusBadRequest)
+				return
+			}
+			data, err := io.ReadAll(file)
+			file.Close()
+			if err != nil {
+				http.Error(w, "Failed to read uploaded image", http.StatusBadRequest)
+				return
+			}
+
+			uploads = append(uploads, services.BatchImageInput{Name: header.Filename, Data: data})
+			inputSize += header.Size
+		}
+
+		inputs, err := services.ExpandBatchUploads(uploads)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"converted-images.zip\"")
+		zw := zip.NewWriter(w)
+
+		var report imageBatchReport
+		var outputSize int64
+		err = services.ConvertImageBatch(r.Context(), inputs, opts, func(result services.BatchImageResult) error {
+			data := result.Data
+			// the report only needs the metadata; keeping Data would hold
+			// every converted image in memory until the batch ends
+			result.Data = nil
+			report.Files = append(report.Files, result)
+			if result.Error != "" {
+				report.Failed++
+				return nil
+			}
+			report.Converted++
+			outputSize += result.OutputSize
+
+			fw, err := zw.CreateHeader(&zip.FileHeader{
+				Name: result.OutputName,
+				// images are already compressed
+				Method:   zip.Store,
+				Modified: startTime,
+			})
+			if err != nil {
+				return err
+			}
+			_, err = fw.Write(data)
+			return err
+		})
+
+		if err != nil {
+			// headers are already sent, so the archive is left truncated
+			logToolUsage(r, queries, "image_converter_batch", inputSize, outputSize, startTime, err)
+			fmt.Printf("Error streaming image batch: %v\n", err)
+			return
+		}
+
+		fw, err := zw.Create("report.json")
+		if err == nil {
+			encoder := json.NewEncoder(fw)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(report)
+		}
+		if err == nil {
+			err = zw.Close()
+		}
+		if err == nil && report.Converted == 0 {
+			err = fmt.Errorf("none of the %d images could be converted", len(inputs))
+		}
+
+		logToolUsage(r, queries, "image_converter_batch", inputSize, outputSize, startTime, err)
+		if err != nil {
+			fmt.Printf("Error finishing image batch: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func ImageCompressorPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.ImageCompressorPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+func ImageCompressHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 10MB limit
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			http.Error(w, "No image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		targetKB, _ := strconv.Atoi(r.FormValue("target_kb"))
+		quality, _ := strconv.Atoi(r.FormValue("quality"))
+		maxDimension, _ := strconv.Atoi(r.FormValue("max_dimension"))
+		colors, _ := strconv.Atoi(r.FormValue("colors"))
+
+		result, err := services.CompressImage(file, services.ImageCompressOptions{
+			Format:         r.FormValue("format"),
+			TargetKB:       targetKB,
+			Quality:        quality,
+			MaxDimension:   maxDimension,
+			AllowDownscale: r.FormValue("allow_downscale") == "true",
+			Colors:         colors,
+		})
+
+		if err != nil {
+			logToolUsage(r, queries, "image_compressor", header.Size, 0, startTime, err)
+			writeImageError(w, "Compression failed", http.StatusInternalServerError, err)
+			return
+		}
+
+		logToolUsage(r, queries, "image_compressor", header.Size, result.CompressedSize, startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":         true,
+			"format":          result.Format,
+			"original_size":   result.OriginalSize,
+			"compressed_size": result.CompressedSize,
+			"quality":         result.Quality,
+			"colors":          result.Colors,
+			"width":           result.Width,
+			"height":          result.Height,
+			"target_met":      result.TargetMet,
+			"image":           result.Data,
+		})
+	}
+}
@@ -3,9 +3,13 @@ package handlers
 import (
 	"bytes"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tmunongo/nanotools/internal/db"
@@ -37,21 +41,17 @@ func ImageConvertHandler(queries *db.Queries) http.HandlerFunc {
 		}
 		defer file.Close()
 
-		outputFormat := r.FormValue("format")
-		qualityStr := r.FormValue("quality")
-
-		quality, err := strconv.Atoi(qualityStr)
+		opts, err := parseImageConvertOptions(r)
 		if err != nil {
-			quality = 85
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
+		outputFormat := opts.OutputFormat
 
 		// for very large images better to use a temp file
 		var outputBuffer bytes.Buffer
 
-		err = services.ConvertImage(file, &outputBuffer, services.ImageConvertOptions{
-			OutputFormat: outputFormat,
-			Quality:      quality,
-		})
+		err = services.ConvertImage(file, &outputBuffer, opts)
 
 		if err != nil {
 			_, _ = queries.CreateAuditLog(r.Context(), db.CreateAuditLogParams{
@@ -64,7 +64,7 @@ func ImageConvertHandler(queries *db.Queries) http.HandlerFunc {
 				ErrorMessage:     sql.NullString{String: err.Error(), Valid: true},
 			})
 
-			http.Error(w, fmt.Sprintf("Conversion failed: %v", err), http.StatusInternalServerError)
+			writeImageError(w, "Conversion failed", http.StatusInternalServerError, err)
 			return
 		}
 
@@ -79,18 +79,10 @@ func ImageConvertHandler(queries *db.Queries) http.HandlerFunc {
 			Status:           "success",
 		})
 
-		contentType := ""
-		switch outputFormat {
-		case "jpeg", "jpg":
-			contentType = "image/jpeg"
-		case "png":
-			contentType = "image/png"
-		case "webp":
-			contentType = "image/webp"
-		}
+		contentType, ext := services.ImageContentType(outputFormat)
 
 		w.Header().Set("Content-Type", contentType)
-		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"converted.%s\"", outputFormat))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"converted.%s\"", ext))
 		w.Header().Set("Content-Length", strconv.Itoa(outputBuffer.Len()))
 
 		_, err = w.Write(outputBuffer.Bytes())
@@ -100,3 +92,76 @@ func ImageConvertHandler(queries *db.Queries) http.HandlerFunc {
 		}
 	}
 }
+
+// parseImageConvertOptions reads the conversion form fields shared by the
+// single and batch converters
+func parseImageConvertOptions(r *http.Request) (services.ImageConvertOptions, error) {
+	frame, _ := strconv.Atoi(r.FormValue("frame"))
+	quality, err := strconv.Atoi(r.FormValue("quality"))
+	if err != nil {
+		quality = 85
+	}
+
+	// optional edit chain, e.g. [{"op":"resize","mode":"fit","width":800,"height":600}]
+	var operations []services.ImageOperation
+	if opsJSON := r.FormValue("operations"); opsJSON != "" {
+		if err := json.Unmarshal([]byte(opsJSON), &operations); err != nil {
+			return services.ImageConvertOptions{}, fmt.Errorf("Invalid operations")
+		}
+	}
+	if len(operations) > services.MaxImageOperations {
+		return services.ImageConvertOptions{}, fmt.Errorf("At most %d operations can be applied at once", services.MaxImageOperations)
+	}
+
+	watermark, err := parseWatermark(r)
+	if err != nil {
+		return services.ImageConvertOptions{}, err
+	}
+
+	return services.ImageConvertOptions{
+		OutputFormat: strings.ToLower(r.FormValue("format")),
+		Quality:      quality,
+		Operations:   operations,
+		Frame:        frame,
+		Watermark:    watermark,
+	}, nil
+}
+
+// parseWatermark reads the optional "watermark" JSON field, e.g.
+// {"text":"DRAFT","opacity":0.3,"tile":true,"angle":30}, and the optional
+// "watermark_logo" file used in place of text
+func parseWatermark(r *http.Request) (*services.Watermark, error) {
+	wmJSON := r.FormValue("watermark")
+	if wmJSON == "" {
+		return nil, nil
+	}
+
+	var watermark services.Watermark
+	if err := json.Unmarshal([]byte(wmJSON), &watermark); err != nil {
+		return nil, fmt.Errorf("Invalid watermark")
+	}
+
+	if logo, _, err := r.FormFile("watermark_logo"); err == nil {
+		defer logo.Close()
+		if watermark.Logo, err = io.ReadAll(logo); err != nil {
+			return nil, fmt.Errorf("Failed to read watermark logo")
+		}
+	}
+
+	return &watermark, nil
+}
+
+// writeImageError maps the image service errors that have their own status
+// codes, falling back to status with message as the prefix
+func writeImageError(w http.ResponseWriter, message string, status int, err error) {
+	switch {
+	case errors.Is(err, services.ErrImageTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, services.ErrInvalidImageOperation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrAVIFUnavailable), errors.Is(err, services.ErrFFmpegUnavailable):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, fmt.Sprintf("%s: %v", message, err), status)
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func ImageDiffPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.ImageDiffPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// ImageDiffHandler compares the "before" and "after" uploads and returns the
+// metrics together with the diff image as a base64 PNG
+func ImageDiffHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 20MB limit, 10MB per image
+		if err := r.ParseMultipartForm(20 << 20); err != nil {
+			http.Error(w, "Files too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		before, beforeHeader, err := r.FormFile("before")
+		if err != nil {
+			http.Error(w, "No first image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer before.Close()
+
+		after, afterHeader, err := r.FormFile("after")
+		if err != nil {
+			http.Error(w, "No second image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer after.Close()
+
+		inputSize := beforeHeader.Size + afterHeader.Size
+
+		threshold := 0
+		if t := r.FormValue("threshold"); t != "" {
+			if threshold, err = strconv.Atoi(t); err != nil {
+				http.Error(w, "Invalid threshold", http.StatusBadRequest)
+				return
+			}
+		}
+
+		result, err := services.CompareImages(before, after, services.ImageDiffOptions{
+			Threshold: threshold,
+			Align:     r.FormValue("align"),
+		})
+		if err != nil {
+			logToolUsage(r, queries, "image_diff", inputSize, 0, startTime, err)
+			writeImageError(w, "Failed to compare images", http.StatusBadRequest, err)
+			return
+		}
+
+		logToolUsage(r, queries, "image_diff", inputSize, int64(len(result.Diff)), startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"metrics": result,
+			"diff":    result.Diff,
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+)
+
+func ImageMetadataHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 10MB limit
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			http.Error(w, "No image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		metadata, err := services.ReadImageMetadata(file)
+		logToolUsage(r, queries, "image_metadata", header.Size, 0, startTime, err)
+		if err != nil {
+			writeImageError(w, "Failed to read metadata", http.StatusBadRequest, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":  true,
+			"metadata": metadata,
+		})
+	}
+}
+
+func ImageStripHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 10MB limit
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			http.Error(w, "No image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		var outputBuffer bytes.Buffer
+		format, err := services.StripImageMetadata(file, &outputBuffer, r.FormValue("mode"))
+		logToolUsage(r, queries, "image_strip_metadata", header.Size, int64(outputBuffer.Len()), startTime, err)
+		if err != nil {
+			writeImageError(w, "Failed to strip metadata", http.StatusBadRequest, err)
+			return
+		}
+
+		contentType, ext := services.ImageContentType(format)
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"stripped.%s\"", ext))
+		w.Header().Set("Content-Length", strconv.Itoa(outputBuffer.Len()))
+		w.Write(outputBuffer.Bytes())
+	}
+}
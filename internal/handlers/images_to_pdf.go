@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func ImagesToPDFPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.ImagesToPDFPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// ImagesToPDFHandler builds a PDF from the uploaded "images". Pages follow
+// upload order unless "order" lists 1-based upload positions.
+func ImagesToPDFHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			http.Error(w, "Files too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		headers := r.MultipartForm.File["images"]
+		if len(headers) == 0 {
+			http.Error(w, "No images uploaded", http.StatusBadRequest)
+			return
+		}
+
+		headers, err := orderFileHeaders(headers, r.FormValue("order"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var inputSize int64
+		inputs := make([]io.Reader, 0, len(headers))
+		for _, header := range headers {
+			file, err := header.Open()
+			if err != nil {
+				http.Error(w, "Failed to read uploaded image", http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+
+			inputs = append(inputs, file)
+			inputSize += header.Size
+		}
+
+		margin, _ := strconv.Atoi(r.FormValue("margin"))
+		quality, err := strconv.Atoi(r.FormValue("quality"))
+		if err != nil {
+			quality = 85
+		}
+
+		pdf, err := services.ImagesToPDF(inputs, services.ImagesToPDFOptions{
+			PageSize:    r.FormValue("page_size"),
+			Orientation: r.FormValue("orientation"),
+			Margin:      margin,
+			Quality:     quality,
+		})
+		if err != nil {
+			logToolUsage(r, queries, "images_to_pdf", inputSize, 0, startTime, err)
+			writeImageError(w, "Conversion failed", http.StatusInternalServerError, err)
+			return
+		}
+
+		logToolUsage(r, queries, "images_to_pdf", inputSize, int64(len(pdf)), startTime, nil)
+		writePDF(w, pdf, "images.pdf")
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func PDFCompressorPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.PDFCompressorPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+func PDFCompressHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		file, header, ok := pdfUpload(w, r)
+		if !ok {
+			return
+		}
+		defer file.Close()
+
+		dpi, _ := strconv.Atoi(r.FormValue("dpi"))
+		if dpi != 0 && (dpi < services.MinPDFImageDPI || dpi > services.MaxPDFImageDPI) {
+			http.Error(w, fmt.Sprintf("Image DPI must be between %d and %d", services.MinPDFImageDPI, services.MaxPDFImageDPI), http.StatusBadRequest)
+			return
+		}
+
+		result, err := services.CompressPDF(file, services.PDFCompressOptions{
+			Preset:    r.FormValue("preset"),
+			ImageDPI:  dpi,
+			Grayscale: r.FormValue("grayscale") == "true",
+		})
+
+		if err != nil {
+			logToolUsage(r, queries, "pdf_compressor", header.Size, 0, startTime, err)
+
+			if errors.Is(err, services.ErrPDFNotSmaller) {
+				http.Error(w, "This PDF is already well optimized; compression would make it larger. Try a lower preset or DPI.", http.StatusUnprocessableEntity)
+				return
+			}
+
+			http.Error(w, fmt.Sprintf("Compression failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		logToolUsage(r, queries, "pdf_compressor", header.Size, result.CompressedSize, startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":         true,
+			"original_size":   result.OriginalSize,
+			"compressed_size": result.CompressedSize,
+			"pdf":             result.Data,
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"archive/zip"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -43,11 +46,73 @@ func PDFToImagesHandler(queries *db.Queries) http.HandlerFunc {
 		dpi, _ := strconv.Atoi(dpiStr)
 		quality, _ := strconv.Atoi(qualityStr)
 
-		images, err := services.ConvertPDFToImages(file, services.PDFToImagesOptions{
-			DPI:     dpi,
-			Format:  format,
-			Quality: quality,
-		})
+		textAA, _ := strconv.Atoi(r.FormValue("text_aa"))
+		graphicsAA, _ := strconv.Atoi(r.FormValue("graphics_aa"))
+		fitWidth, _ := strconv.Atoi(r.FormValue("fit_width"))
+		fitHeight, _ := strconv.Atoi(r.FormValue("fit_height"))
+
+		watermark, err := parseWatermark(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		opts := services.PDFToImagesOptions{
+			DPI:               dpi,
+			Format:            format,
+			Quality:           quality,
+			Password:          r.FormValue("password"),
+			ColorMode:         r.FormValue("color_mode"),
+			TextAlphaBits:     textAA,
+			GraphicsAlphaBits: graphicsAA,
+			FitWidth:          fitWidth,
+			FitHeight:         fitHeight,
+			Watermark:         watermark,
+		}
+
+		if r.FormValue("contact_sheet") == "true" {
+			columns, _ := strconv.Atoi(r.FormValue("columns"))
+			thumbWidth, _ := strconv.Atoi(r.FormValue("thumb_width"))
+
+			sheet, err := services.RenderPDFContactSheet(r.Context(), file, opts, services.ContactSheetOptions{
+				Columns:    columns,
+				ThumbWidth: thumbWidth,
+				Format:     format,
+				Quality:    quality,
+			})
+			logToolUsage(r, queries, "pdf_contact_sheet", header.Size, int64(len(sheet)), startTime, err)
+			if err != nil {
+				writePDFConvertError(w, err)
+				return
+			}
+
+			ext, contentType := "png", "image/png"
+			switch format {
+			case "jpeg", "jpg":
+				ext, contentType = "jpg", "image/jpeg"
+			case "webp":
+				ext, contentType = "webp", "image/webp"
+			}
+
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"contact-sheet.%s\"", ext))
+			w.Header().Set("Content-Length", strconv.Itoa(len(sheet)))
+			w.Write(sheet)
+			return
+		}
+
+		// large documents can be streamed page by page instead of
+		// being returned as a single JSON document
+		switch r.FormValue("output") {
+		case "ndjson":
+			streamPDFImagesNDJSON(w, r, queries, file, header.Size, opts, startTime)
+			return
+		case "zip":
+			streamPDFImagesZip(w, r, queries, file, header.Size, opts, startTime)
+			return
+		}
+
+		images, err := services.ConvertPDFToImages(file, opts)
 
 		if err != nil {
 			_, _ = queries.CreateAuditLog(r.Context(), db.CreateAuditLogParams{
@@ -60,7 +125,7 @@ func PDFToImagesHandler(queries *db.Queries) http.HandlerFunc {
 				ErrorMessage:     sql.NullString{String: err.Error(), Valid: true},
 			})
 
-			http.Error(w, fmt.Sprintf("Conversion failed: %v", err), http.StatusInternalServerError)
+			writePDFConvertError(w, err)
 			return
 		}
 
@@ -88,3 +153,101 @@ func PDFToImagesHandler(queries *db.Queries) http.HandlerFunc {
 		})
 	}
 }
+
+// streamPDFImagesNDJSON writes one JSON object per page as soon as it is
+// ready. Errors after the first page are reported as a final {"error": ...}
+// line since the status code has already been sent.
+func streamPDFImagesNDJSON(w http.ResponseWriter, r *http.Request, queries *db.Queries, file io.Reader, inputSize int64, opts services.PDFToImagesOptions, startTime time.Time) {
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	started := false
+	var totalSize int64
+
+	err := services.StreamPDFToImages(r.Context(), file, opts, func(img services.PDFPageImage) error {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			started = true
+		}
+
+		totalSize += int64(len(img.ImageData))
+		if err := encoder.Encode(img); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+
+	logToolUsage(r, queries, "pdf_to_images", inputSize, totalSize, startTime, err)
+
+	if err != nil {
+		if !started {
+			writePDFConvertError(w, err)
+			return
+		}
+		encoder.Encode(map[string]string{"error": err.Error()})
+	}
+}
+
+// streamPDFImagesZip writes pages into a ZIP archive as they are rendered.
+// A failure after the first page truncates the archive.
+func streamPDFImagesZip(w http.ResponseWriter, r *http.Request, queries *db.Queries, file io.Reader, inputSize int64, opts services.PDFToImagesOptions, startTime time.Time) {
+	var zw *zip.Writer
+	var totalSize int64
+
+	err := services.StreamPDFToImages(r.Context(), file, opts, func(img services.PDFPageImage) error {
+		if zw == nil {
+			w.Header().Set("Content-Type", "application/zip")
+			w.Header().Set("Content-Disposition", "attachment; filename=\"pages.zip\"")
+			zw = zip.NewWriter(w)
+		}
+
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name: fmt.Sprintf("page-%04d.%s", img.PageNumber, img.Format),
+			// images are already compressed
+			Method: zip.Store,
+		})
+		if err != nil {
+			return err
+		}
+
+		totalSize += int64(len(img.ImageData))
+		_, err = fw.Write(img.ImageData)
+		return err
+	})
+
+	logToolUsage(r, queries, "pdf_to_images", inputSize, totalSize, startTime, err)
+
+	if err != nil {
+		if zw == nil {
+			writePDFConvertError(w, err)
+			return
+		}
+		fmt.Printf("Error streaming PDF pages: %v\n", err)
+		return
+	}
+
+	if zw == nil {
+		http.Error(w, "No pages were rendered", http.StatusInternalServerError)
+		return
+	}
+
+	if err := zw.Close(); err != nil {
+		fmt.Printf("Error finishing ZIP: %v\n", err)
+	}
+}
+
+func writePDFConvertError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrPDFPasswordRequired) || errors.Is(err, services.ErrPDFWrongPassword) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, services.ErrImageTooLarge) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	http.Error(w, fmt.Sprintf("Conversion failed: %v", err), http.StatusInternalServerError)
+}
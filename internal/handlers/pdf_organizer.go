@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func PDFOrganizerPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.PDFOrganizerPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// PDFMergeHandler merges the uploaded "pdfs" files. The optional "order"
+// field lists 1-based upload positions, e.g. "2,1,3"; otherwise upload order
+// is used.
+func PDFMergeHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		headers := r.MultipartForm.File["pdfs"]
+		if len(headers) < 2 {
+			http.Error(w, "Upload at least two PDFs to merge", http.StatusBadRequest)
+			return
+		}
+
+		headers, err := orderFileHeaders(headers, r.FormValue("order"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var inputSize int64
+		inputs := make([]io.Reader, 0, len(headers))
+		for _, header := range headers {
+			file, err := header.Open()
+			if err != nil {
+				http.Error(w, "Failed to read uploaded PDF", http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+
+			inputs = append(inputs, file)
+			inputSize += header.Size
+		}
+
+		merged, err := services.MergePDFs(inputs)
+		if err != nil {
+			logToolUsage(r, queries, "pdf_merge", inputSize, 0, startTime, err)
+			writePDFOrganizeError(w, "Merge failed", err)
+			return
+		}
+
+		logToolUsage(r, queries, "pdf_merge", inputSize, int64(len(merged)), startTime, nil)
+		writePDF(w, merged, "merged.pdf")
+	}
+}
+
+// PDFSplitHandler splits "pdf" by the "ranges" field into a ZIP of parts
+func PDFSplitHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		file, header, ok := pdfUpload(w, r)
+		if !ok {
+			return
+		}
+		defer file.Close()
+
+		parts, err := services.SplitPDF(file, r.FormValue("ranges"))
+		if err != nil {
+			logToolUsage(r, queries, "pdf_split", header.Size, 0, startTime, err)
+			writePDFOrganizeError(w, "Split failed", err)
+			return
+		}
+
+		if len(parts) == 1 {
+			logToolUsage(r, queries, "pdf_split", header.Size, int64(len(parts[0].Data)), startTime, nil)
+			writePDF(w, parts[0].Data, parts[0].Name)
+			return
+		}
+
+		entries := make([]services.ArchiveEntry, 0, len(parts))
+		for _, part := range parts {
+			entries = append(entries, services.ArchiveEntry{Name: part.Name, Data: part.Data})
+		}
+
+		var buf bytes.Buffer
+		if err := services.WriteZip(&buf, entries); err != nil {
+			logToolUsage(r, queries, "pdf_split", header.Size, 0, startTime, err)
+			http.Error(w, fmt.Sprintf("Split failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		logToolUsage(r, queries, "pdf_split", header.Size, int64(buf.Len()), startTime, nil)
+		writeZip(w, buf.Bytes(), "split.zip")
+	}
+}
+
+// PDFRotateHandler rotates the "pages" of "pdf" by "angle" degrees
+func PDFRotateHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		file, header, ok := pdfUpload(w, r)
+		if !ok {
+			return
+		}
+		defer file.Close()
+
+		angle, err := strconv.Atoi(r.FormValue("angle"))
+		if err != nil {
+			angle = 90
+		}
+		if angle%90 != 0 {
+			http.Error(w, "Rotation must be a multiple of 90 degrees", http.StatusBadRequest)
+			return
+		}
+
+		rotated, err := services.RotatePDFPages(file, r.FormValue("pages"), angle)
+		if err != nil {
+			logToolUsage(r, queries, "pdf_rotate", header.Size, 0, startTime, err)
+			writePDFOrganizeError(w, "Rotation failed", err)
+			return
+		}
+
+		logToolUsage(r, queries, "pdf_rotate", header.Size, int64(len(rotated)), startTime, nil)
+		writePDF(w, rotated, "rotated.pdf")
+	}
+}
+
+// PDFReorderHandler rebuilds "pdf" with the pages listed in "order"; pages
+// missing from the list are deleted
+func PDFReorderHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		file, header, ok := pdfUpload(w, r)
+		if !ok {
+			return
+		}
+		defer file.Close()
+
+		reordered, err := services.ReorderPDFPages(file, r.FormValue("order"))
+		if err != nil {
+			logToolUsage(r, queries, "pdf_reorder", header.Size, 0, startTime, err)
+			writePDFOrganizeError(w, "Reorder failed", err)
+			return
+		}
+
+		logToolUsage(r, queries, "pdf_reorder", header.Size, int64(len(reordered)), startTime, nil)
+		writePDF(w, reordered, "reordered.pdf")
+	}
+}
+
+// pdfUpload parses the multipart form and returns the "pdf" file. It writes
+// the error response itself and reports false when the request is unusable.
+func pdfUpload(w http.ResponseWriter, r *http.Request) (multipart.File, *multipart.FileHeader, bool) {
+	if err := r.ParseMultipartForm(50 << 20); err != nil {
+		http.Error(w, "File too large or invalid", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	file, header, err := r.FormFile("pdf")
+	if err != nil {
+		http.Error(w, "No PDF uploaded", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	return file, header, true
+}
+
+// orderFileHeaders rearranges uploads according to a comma separated list of
+// 1-based positions. Every upload must appear exactly once.
+func orderFileHeaders(headers []*multipart.FileHeader, order string) ([]*multipart.FileHeader, error) {
+	if strings.TrimSpace(order) == "" {
+		return headers, nil
+	}
+
+	positions := strings.Split(order, ",")
+	if len(positions) != len(headers) {
+		return nil, fmt.Errorf("order must list each of the %d files exactly once", len(headers))
+	}
+
+	seen := make(map[int]bool)
+	ordered := make([]*multipart.FileHeader, 0, len(headers))
+	for _, p := range positions {
+		pos, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || pos < 1 || pos > len(headers) || seen[pos] {
+			return nil, fmt.Errorf("order must list each of the %d files exactly once", len(headers))
+		}
+		seen[pos] = true
+		ordered = append(ordered, headers[pos-1])
+	}
+
+	return ordered, nil
+}
+
+// writePDFOrganizeError reports bad page selections as client errors and
+// anything else as a failure of the operation named by message
+func writePDFOrganizeError(w http.ResponseWriter, message string, err error) {
+	if errors.Is(err, services.ErrInvalidPageSelection) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	http.Error(w, fmt.Sprintf("%s: %v", message, err), http.StatusInternalServerError)
+}
+
+func writePDF(w http.ResponseWriter, data []byte, filename string) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+
+	if _, err := w.Write(data); err != nil {
+		fmt.Printf("Error writing response: %v\n", err)
+	}
+}
+
+func writeZip(w http.ResponseWriter, data []byte, filename string) {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+
+	if _, err := w.Write(data); err != nil {
+		fmt.Printf("Error writing response: %v\n", err)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func PDFTextPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.PDFTextPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// PDFTextHandler extracts text from "pdf". The "output" field selects plain
+// "text" (default), "markdown", per-page "json" or a "download" of a .txt file.
+func PDFTextHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		file, header, ok := pdfUpload(w, r)
+		if !ok {
+			return
+		}
+		defer file.Close()
+
+		firstPage, _ := strconv.Atoi(r.FormValue("first_page"))
+		lastPage, _ := strconv.Atoi(r.FormValue("last_page"))
+		output := r.FormValue("output")
+
+		pages, err := services.ExtractPDFText(file, services.PDFTextOptions{
+			FirstPage: firstPage,
+			LastPage:  lastPage,
+		})
+		if err != nil {
+			logToolUsage(r, queries, "pdf_to_text", header.Size, 0, startTime, err)
+			http.Error(w, fmt.Sprintf("Extraction failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		emptyPages := []int{}
+		for _, page := range pages {
+			if page.Empty {
+				emptyPages = append(emptyPages, page.PageNumber)
+			}
+		}
+
+		text := services.FormatPDFText(pages, output)
+		logToolUsage(r, queries, "pdf_to_text", header.Size, int64(len(text)), startTime, nil)
+
+		if output == "download" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("Content-Disposition", "attachment; filename=\"extracted.txt\"")
+			w.Header().Set("Content-Length", strconv.Itoa(len(text)))
+			w.Write([]byte(text))
+			return
+		}
+
+		response := map[string]interface{}{
+			"success":     true,
+			"count":       len(pages),
+			"empty_pages": emptyPages,
+		}
+		if output == "json" {
+			response["pages"] = pages
+		} else {
+			response["text"] = text
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"image/color"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -15,6 +16,9 @@ import (
 	"github.com/tmunongo/nanotools/web/templates/tools"
 )
 
+// maxQRLogoSize caps the optional logo upload
+const maxQRLogoSize = 5 << 20
+
 // QRCodePageHandler serves the QR code generator page
 func QRCodePageHandler(w http.ResponseWriter, r *http.Request) {
 	err := tools.QRCodePage().Render(r.Context(), w)
@@ -46,6 +50,20 @@ func QRCodeGenerateHandler(queries *db.Queries) http.HandlerFunc {
 			size = 512
 		}
 
+		// Optional logo drawn over the centre of the code
+		var logo []byte
+		if file, header, err := r.FormFile("logo"); err == nil {
+			defer file.Close()
+			if header.Size > maxQRLogoSize {
+				http.Error(w, fmt.Sprintf("Logo must be smaller than %d MB", maxQRLogoSize>>20), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if logo, err = io.ReadAll(file); err != nil {
+				http.Error(w, "Failed to read logo", http.StatusBadRequest)
+				return
+			}
+		}
+
 		// Parse error correction
 		errorCorrectionLevel, err := strconv.Atoi(errorCorrectionStr)
 		if err != nil || errorCorrectionLevel < 0 || errorCorrectionLevel > 3 {
@@ -70,6 +88,7 @@ func QRCodeGenerateHandler(queries *db.Queries) http.HandlerFunc {
 				ErrorCorrection: qrcode.RecoveryLevel(errorCorrectionLevel),
 				ForegroundColor: parseColor(fgColor),
 				BackgroundColor: parseColor(bgColor),
+				Logo:            logo,
 			})
 			contentDescription = "text"
 
@@ -83,7 +102,7 @@ func QRCodeGenerateHandler(queries *db.Queries) http.HandlerFunc {
 				return
 			}
 
-			qrData, err = services.GenerateWiFiQRCode(ssid, password, encryption, size)
+			qrData, err = services.GenerateWiFiQRCode(ssid, password, encryption, size, logo)
 			contentDescription = fmt.Sprintf("wifi:%s", ssid)
 
 		case "vcard":
@@ -96,7 +115,7 @@ func QRCodeGenerateHandler(queries *db.Queries) http.HandlerFunc {
 				return
 			}
 
-			qrData, err = services.GenerateVCardQRCode(name, phone, email, size)
+			qrData, err = services.GenerateVCardQRCode(name, phone, email, size, logo)
 			contentDescription = fmt.Sprintf("vcard:%s", name)
 
 		default:
@@ -115,7 +134,7 @@ func QRCodeGenerateHandler(queries *db.Queries) http.HandlerFunc {
 				ErrorMessage:     sql.NullString{String: err.Error(), Valid: true},
 			})
 
-			http.Error(w, fmt.Sprintf("Failed to generate QR code: %v", err), http.StatusInternalServerError)
+			writeImageError(w, "Failed to generate QR code", http.StatusInternalServerError, err)
 			return
 		}
 
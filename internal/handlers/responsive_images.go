@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func ResponsiveImagesPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.ResponsiveImagesPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// ResponsiveImagesHandler returns the manifest together with the whole set
+// as a base64 ZIP. "widths" is a comma separated list and "formats" may be
+// repeated.
+func ResponsiveImagesHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 10MB limit
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			http.Error(w, "No image uploaded", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		var widths []int
+		for _, field := range strings.Split(r.FormValue("widths"), ",") {
+			if field = strings.TrimSpace(field); field == "" {
+				continue
+			}
+			width, err := strconv.Atoi(field)
+			if err != nil || width < 1 {
+				http.Error(w, "Invalid width: "+field, http.StatusBadRequest)
+				return
+			}
+			widths = append(widths, width)
+		}
+		if len(widths) > services.MaxResponsiveWidths {
+			http.Error(w, fmt.Sprintf("At most %d widths can be generated at once", services.MaxResponsiveWidths), http.StatusBadRequest)
+			return
+		}
+
+		quality, _ := strconv.Atoi(r.FormValue("quality"))
+
+		set, err := services.GenerateResponsiveImages(file, services.ResponsiveImageOptions{
+			Widths:   widths,
+			Formats:  r.MultipartForm.Value["formats"],
+			Quality:  quality,
+			BaseName: header.Filename,
+			Sizes:    r.FormValue("sizes"),
+			Alt:      r.FormValue("alt"),
+		})
+		if err != nil {
+			logToolUsage(r, queries, "responsive_images", header.Size, 0, startTime, err)
+			writeImageError(w, "Failed to generate images", http.StatusInternalServerError, err)
+			return
+		}
+
+		var archive bytes.Buffer
+		if err := services.WriteZip(&archive, set.Files); err != nil {
+			logToolUsage(r, queries, "responsive_images", header.Size, 0, startTime, err)
+			http.Error(w, "Failed to create archive", http.StatusInternalServerError)
+			return
+		}
+
+		logToolUsage(r, queries, "responsive_images", header.Size, int64(archive.Len()), startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":  true,
+			"manifest": set.Manifest,
+			"archive":  archive.Bytes(),
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func SVGToolsPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.SVGToolsPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// SVGOptimizeHandler minifies the "svg" upload, or pasted "markup". With
+// mode "sanitize" the markup is only made safe, not otherwise changed.
+func SVGOptimizeHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		data, name, err := readSVGInput(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		precision := 3
+		if p := r.FormValue("precision"); p != "" {
+			if precision, err = strconv.Atoi(p); err != nil {
+				http.Error(w, "Invalid precision", http.StatusBadRequest)
+				return
+			}
+		}
+
+		var output []byte
+		var report *services.SVGSanitizeReport
+		if r.FormValue("mode") == "sanitize" {
+			output, report, err = services.SanitizeSVG(data)
+		} else {
+			output, report, err = services.OptimizeSVG(data, services.SVGOptimizeOptions{
+				Precision: precision,
+				KeepTitle: r.FormValue("keep_title") == "true",
+			})
+		}
+		if err != nil {
+			logToolUsage(r, queries, "svg_optimize", int64(len(data)), 0, startTime, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logToolUsage(r, queries, "svg_optimize", int64(len(data)), int64(len(output)), startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":       true,
+			"name":          name,
+			"svg":           string(output),
+			"original_size": len(data),
+			"size":          len(output),
+			"removed":       report.Removed,
+		})
+	}
+}
+
+// SVGRasterizeHandler renders the SVG at each of the comma separated
+// "widths" and returns the file list, the largest image as a preview and
+// every image as a base64 ZIP
+func SVGRasterizeHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		data, name, err := readSVGInput(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var widths []int
+		for _, field := range strings.Split(r.FormValue("widths"), ",") {
+			if field = strings.TrimSpace(field); field == "" {
+				continue
+			}
+			width, err := strconv.Atoi(field)
+			if err != nil || width < 1 {
+				http.Error(w, "Invalid width: "+field, http.StatusBadRequest)
+				return
+			}
+			widths = append(widths, width)
+		}
+
+		quality, _ := strconv.Atoi(r.FormValue("quality"))
+
+		rasters, err := services.RasterizeSVGSizes(data, services.SVGRasterizeOptions{
+			Format:   r.FormValue("format"),
+			Widths:   widths,
+			Quality:  quality,
+			BaseName: name,
+		})
+		if err != nil {
+			logToolUsage(r, queries, "svg_rasterize", int64(len(data)), 0, startTime, err)
+			writeImageError(w, "Failed to render SVG", http.StatusBadRequest, err)
+			return
+		}
+
+		entries := make([]services.ArchiveEntry, len(rasters))
+		for i, raster := range rasters {
+			entries[i] = services.ArchiveEntry{Name: raster.Name, Data: raster.Data}
+		}
+
+		var archive bytes.Buffer
+		if err := services.WriteZip(&archive, entries); err != nil {
+			logToolUsage(r, queries, "svg_rasterize", int64(len(data)), 0, startTime, err)
+			http.Error(w, "Failed to create archive", http.StatusInternalServerError)
+			return
+		}
+
+		logToolUsage(r, queries, "svg_rasterize", int64(len(data)), int64(archive.Len()), startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"files":   rasters,
+			"preview": rasters[len(rasters)-1].Data,
+			"archive": archive.Bytes(),
+		})
+	}
+}
+
+// readSVGInput returns the uploaded "svg" file, or the "markup" field when
+// nothing was uploaded, with a name for the output files
+func readSVGInput(r *http.Request) ([]byte, string, error) {
+	// 10MB limit
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		return nil, "", fmt.Errorf("File too large or invalid")
+	}
+
+	if file, header, err := r.FormFile("svg"); err == nil {
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to read file")
+		}
+		return data, header.Filename, nil
+	}
+
+	if markup := r.FormValue("markup"); strings.TrimSpace(markup) != "" {
+		return []byte(markup), "image", nil
+	}
+	return nil, "", fmt.Errorf("No SVG uploaded")
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -173,6 +174,15 @@ func VideoDownloadHandler(queries *db.Queries) http.HandlerFunc {
 			return
 		}
 
+		videoFormatID := r.FormValue("video_format")
+		audioFormatID := r.FormValue("audio_format")
+		for _, id := range []string{videoFormatID, audioFormatID} {
+			if id != "" && !services.ValidFormatID(id) {
+				http.Error(w, "Invalid format ID: "+id, http.StatusBadRequest)
+				return
+			}
+		}
+
 		if quality == "" {
 			quality = "720p"
 		}
@@ -186,7 +196,7 @@ func VideoDownloadHandler(queries *db.Queries) http.HandlerFunc {
 
 		browser := detectBrowserFromUA(r.UserAgent())
 
-		filePath, err := services.DownloadVideo(ctx, services.VideoDownloadOptions{
+		downloadOpts := services.VideoDownloadOptions{
 			URL:                videoURL,
 			Quality:            quality,
 			Format:             format,
@@ -195,7 +205,64 @@ func VideoDownloadHandler(queries *db.Queries) http.HandlerFunc {
 			MaxDuration:        3600,
 			CookiesFromBrowser: browser,
 			CookiesPath:        uploadedCookiesPath,
-		})
+			ForceKeyframes:     r.FormValue("force_keyframes") == "true",
+			VideoFormatID:      videoFormatID,
+			AudioFormatID:      audioFormatID,
+			Codec:              r.FormValue("codec"),
+			AudioFormat:        r.FormValue("audio_ext"),
+			EmbedThumbnail:     r.FormValue("embed_thumbnail") == "true",
+			EmbedMetadata:      r.FormValue("embed_metadata") == "true",
+		}
+
+		if bitrate := r.FormValue("audio_bitrate"); bitrate != "" {
+			var err error
+			if downloadOpts.AudioBitrate, err = strconv.Atoi(bitrate); err != nil {
+				http.Error(w, "Invalid audio bitrate", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// a clip is either a start/end range or one of the video's chapters
+		chapter := r.FormValue("chapter")
+		if r.FormValue("start") != "" || r.FormValue("end") != "" || chapter != "" {
+			var err error
+			if downloadOpts.ClipStart, err = services.ParseTimestamp(r.FormValue("start")); err != nil {
+				http.Error(w, "Invalid start time", http.StatusBadRequest)
+				return
+			}
+			if downloadOpts.ClipEnd, err = services.ParseTimestamp(r.FormValue("end")); err != nil {
+				http.Error(w, "Invalid end time", http.StatusBadRequest)
+				return
+			}
+
+			infoCtx, infoCancel := context.WithTimeout(ctx, 30*time.Second)
+			info, err := services.GetVideoInfo(infoCtx, videoURL, browser, uploadedCookiesPath)
+			infoCancel()
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Failed to get video info: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			if chapter != "" {
+				index, err := strconv.Atoi(chapter)
+				if err != nil || index < 0 || index >= len(info.Chapters) {
+					http.Error(w, "Invalid chapter", http.StatusBadRequest)
+					return
+				}
+				downloadOpts.ClipStart = info.Chapters[index].StartTime
+				downloadOpts.ClipEnd = info.Chapters[index].EndTime
+			} else if downloadOpts.ClipEnd == 0 {
+				// only a start was given, so clip to the end of the video
+				downloadOpts.ClipEnd = float64(info.Duration)
+			}
+
+			if err := services.ValidateClip(info, downloadOpts.ClipStart, downloadOpts.ClipEnd, downloadOpts.MaxDuration); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		filePath, err := services.DownloadVideo(ctx, downloadOpts)
 
 		if err != nil {
 			_, _ = queries.CreateAuditLog(r.Context(), db.CreateAuditLogParams{
@@ -237,8 +304,9 @@ func VideoDownloadHandler(queries *db.Queries) http.HandlerFunc {
 		})
 
 		contentType := "video/mp4"
-		if quality == "audio" {
-			contentType = "audio/mpeg"
+		if quality == "audio" || (videoFormatID == "" && audioFormatID != "") {
+			// extracted audio, or an audio-only format in its own container
+			contentType = services.AudioContentType(filepath.Ext(fileInfo.Name()))
 		}
 
 		w.Header().Set("Content-Type", contentType)
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+func VideoToGIFPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.VideoToGIFPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// VideoToGIFHandler converts a clip of the uploaded "video", or of the video
+// at "url", to an animated GIF or WebP
+func VideoToGIFHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		// 50MB limit, the most the server accepts
+		if err := r.ParseMultipartForm(50 << 20); err != nil {
+			http.Error(w, "File too large or invalid", http.StatusBadRequest)
+			return
+		}
+
+		opts := services.VideoToGIFOptions{
+			Format: r.FormValue("format"),
+		}
+
+		var err error
+		if opts.Start, err = services.ParseTimestamp(r.FormValue("start")); err != nil {
+			http.Error(w, "Invalid start time", http.StatusBadRequest)
+			return
+		}
+		if opts.End, err = services.ParseTimestamp(r.FormValue("end")); err != nil {
+			http.Error(w, "Invalid end time", http.StatusBadRequest)
+			return
+		}
+
+		for field, target := range map[string]*int{
+			"fps":     &opts.FPS,
+			"width":   &opts.Width,
+			"loop":    &opts.Loop,
+			"quality": &opts.Quality,
+		} {
+			if v := r.FormValue(field); v != "" {
+				if *target, err = strconv.Atoi(v); err != nil {
+					http.Error(w, "Invalid "+field, http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		// everything below runs within the server's 60s request timeout, so
+		// URLs only download the clip rather than the whole video
+		ctx := r.Context()
+
+		var output []byte
+		var inputSize int64
+
+		if file, header, ferr := r.FormFile("video"); ferr == nil {
+			defer file.Close()
+			inputSize = header.Size
+			output, err = services.ConvertVideoToGIF(ctx, file, opts)
+		} else {
+			videoURL := r.FormValue("url")
+			if videoURL == "" {
+				http.Error(w, "Upload a video or enter a URL", http.StatusBadRequest)
+				return
+			}
+
+			// Temporarily disable YouTube downloads
+			if services.IsYouTubeURL(videoURL) {
+				http.Error(w, "YouTube downloads are temporarily disabled", http.StatusForbidden)
+				return
+			}
+
+			end := opts.End
+			if end == 0 {
+				end = opts.Start + services.MaxGIFDuration
+			}
+			if end <= opts.Start {
+				http.Error(w, "End time must be after the start time", http.StatusBadRequest)
+				return
+			}
+
+			// the GIF is at most 1280px wide, so a small rendition is plenty
+			filePath, derr := services.DownloadVideo(ctx, services.VideoDownloadOptions{
+				URL:                videoURL,
+				Quality:            "720p",
+				Format:             "mp4",
+				MaxFileSize:        50 * 1024 * 1024,
+				MaxDuration:        3600,
+				ClipStart:          opts.Start,
+				ClipEnd:            end,
+				CookiesFromBrowser: detectBrowserFromUA(r.UserAgent()),
+			})
+			if derr != nil {
+				logToolUsage(r, queries, "video_to_gif", 0, 0, startTime, derr)
+				http.Error(w, fmt.Sprintf("Download failed: %v", derr), http.StatusBadRequest)
+				return
+			}
+			defer services.CleanupDownloadedFile(filePath)
+
+			// the downloaded file starts at the clip start
+			opts.Start, opts.End = 0, end-opts.Start
+			output, err = services.ConvertVideoFileToGIF(ctx, filePath, opts)
+		}
+
+		if err != nil {
+			logToolUsage(r, queries, "video_to_gif", inputSize, 0, startTime, err)
+			writeImageError(w, "Conversion failed", http.StatusBadRequest, err)
+			return
+		}
+
+		logToolUsage(r, queries, "video_to_gif", inputSize, int64(len(output)), startTime, nil)
+
+		ext := "gif"
+		if strings.ToLower(opts.Format) == "webp" {
+			ext = "webp"
+		}
+
+		w.Header().Set("Content-Type", "image/"+ext)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"animation.%s\"", ext))
+		w.Header().Set("Content-Length", strconv.Itoa(len(output)))
+		w.Write(output)
+	}
+}
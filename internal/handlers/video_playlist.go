@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+	"github.com/tmunongo/nanotools/web/templates/tools"
+)
+
+const (
+	// maxPlaylistTotalSize caps the files a single playlist job keeps on disk
+	maxPlaylistTotalSize = 2 << 30
+
+	// maxPlaylistTotalDuration caps the listed length of a job, in seconds
+	maxPlaylistTotalDuration = 6 * 3600
+)
+
+func PlaylistDownloaderPageHandler(w http.ResponseWriter, r *http.Request) {
+	err := tools.PlaylistDownloaderPage().Render(r.Context(), w)
+	if err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// PlaylistInfoHandler lists the entries of the playlist or channel at "url"
+func PlaylistInfoHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		info, status, err := playlistInfo(r)
+		if err != nil {
+			logToolUsage(r, queries, "playlist_info", 0, 0, startTime, err)
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		logToolUsage(r, queries, "playlist_info", 0, 0, startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success":   true,
+			"playlist":  info,
+			"max_items": services.MaxPlaylistItems,
+		})
+	}
+}
+
+// PlaylistJobHandler starts a background download of the "items" selection
+// (e.g. "1-5,8") from the playlist at "url" and returns the job to poll
+func PlaylistJobHandler(queries *db.Queries, jobs *services.PlaylistJobManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		info, status, err := playlistInfo(r)
+		if err != nil {
+			logToolUsage(r, queries, "playlist_download", 0, 0, startTime, err)
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		indexes, err := services.ParsePlaylistSelection(r.FormValue("items"), len(info.Entries))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		entries := make([]services.PlaylistEntry, len(indexes))
+		for i, index := range indexes {
+			entries[i] = info.Entries[index-1]
+		}
+
+		quality := r.FormValue("quality")
+		if quality == "" {
+			quality = "720p"
+		}
+
+		job, err := jobs.Start(services.PlaylistDownloadOptions{
+			Title:   info.Title,
+			Entries: entries,
+			Download: services.VideoDownloadOptions{
+				Quality:     quality,
+				Format:      "mp4",
+				AudioFormat: r.FormValue("audio_ext"),
+				MaxFileSize: 500 * 1024 * 1024,
+				MaxDuration: 3600,
+			},
+			MaxTotalSize:     maxPlaylistTotalSize,
+			MaxTotalDuration: maxPlaylistTotalDuration,
+		})
+		if err != nil {
+			logToolUsage(r, queries, "playlist_download", 0, 0, startTime, err)
+			if errors.Is(err, services.ErrPlaylistBusy) {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logToolUsage(r, queries, "playlist_download", 0, 0, startTime, nil)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"job":     job,
+		})
+	}
+}
+
+// PlaylistJobStatusHandler returns the per-entry status of a playlist job
+func PlaylistJobStatusHandler(jobs *services.PlaylistJobManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := jobs.Get(chi.URLParam(r, "id"))
+		if !ok {
+			http.Error(w, "Download not found; it may have expired", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"job":     job,
+		})
+	}
+}
+
+// PlaylistJobCancelHandler stops a running playlist job; entries already
+// downloaded stay available
+func PlaylistJobCancelHandler(jobs *services.PlaylistJobManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := jobs.Cancel(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+		})
+	}
+}
+
+// PlaylistFileHandler serves one downloaded entry of a playlist job
+func PlaylistFileHandler(jobs *services.PlaylistJobManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		index, err := strconv.Atoi(chi.URLParam(r, "index"))
+		if err != nil {
+			http.Error(w, "Invalid entry", http.StatusBadRequest)
+			return
+		}
+
+		archiveFile, err := jobs.File(chi.URLParam(r, "id"), index)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		file, err := os.Open(archiveFile.Path)
+		if err != nil {
+			http.Error(w, "Failed to open downloaded file", http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		fileInfo, err := file.Stat()
+		if err != nil {
+			http.Error(w, "Failed to get file info", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", downloadContentType(archiveFile.Name))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", archiveFile.Name))
+		w.Header().Set("Content-Length", strconv.FormatInt(fileInfo.Size(), 10))
+
+		if _, err := io.Copy(w, file); err != nil {
+			fmt.Printf("Error streaming file: %v\n", err)
+		}
+	}
+}
+
+// PlaylistArchiveHandler streams every downloaded entry of a playlist job as
+// a ZIP
+func PlaylistArchiveHandler(queries *db.Queries, jobs *services.PlaylistJobManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		files, err := jobs.Files(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"playlist.zip\"")
+
+		// headers are already sent, so a failure can only be logged
+		err = services.WriteZipFiles(w, files)
+		logToolUsage(r, queries, "playlist_archive", 0, 0, startTime, err)
+		if err != nil {
+			fmt.Printf("Error streaming archive: %v\n", err)
+		}
+	}
+}
+
+// playlistInfo lists the playlist at the "url" form value, returning the
+// HTTP status to use on failure
+func playlistInfo(r *http.Request) (*services.PlaylistInfo, int, error) {
+	playlistURL := strings.TrimSpace(r.FormValue("url"))
+	if playlistURL == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("URL is required")
+	}
+
+	// Temporarily disable YouTube downloads
+	if services.IsYouTubeURL(playlistURL) {
+		return nil, http.StatusForbidden, fmt.Errorf("YouTube downloads are temporarily disabled")
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 45*time.Second)
+	defer cancel()
+
+	info, err := services.GetPlaylistInfo(ctx, playlistURL)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	for _, entry := range info.Entries {
+		if services.IsYouTubeURL(entry.URL) {
+			return nil, http.StatusForbidden, fmt.Errorf("YouTube downloads are temporarily disabled")
+		}
+	}
+
+	return info, http.StatusOK, nil
+}
+
+// downloadContentType picks the Content-Type for a downloaded video or audio
+// file from its extension
+func downloadContentType(name string) string {
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".mp4":
+		return "video/mp4"
+	case ".mkv":
+		return "video/x-matroska"
+	default:
+		return services.AudioContentType(ext)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tmunongo/nanotools/internal/db"
+	"github.com/tmunongo/nanotools/internal/services"
+)
+
+// VideoSubtitlesHandler downloads the "lang" subtitles of the video at "url"
+// without the video and returns them as "format": vtt, srt (the default),
+// ass or a txt transcript
+func VideoSubtitlesHandler(queries *db.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+
+		videoURL := r.FormValue("url")
+		lang := r.FormValue("lang")
+		auto := r.FormValue("auto") == "true"
+
+		if videoURL == "" || lang == "" {
+			http.Error(w, "URL and language are required", http.StatusBadRequest)
+			return
+		}
+
+		// Temporarily disable YouTube downloads
+		if services.IsYouTubeURL(videoURL) {
+			http.Error(w, "YouTube downloads are temporarily disabled", http.StatusForbidden)
+			return
+		}
+
+		format := r.FormValue("format")
+		if format == "" {
+			format = "srt"
+		}
+		contentType, ok := services.SubtitleContentTypes[format]
+		if !ok {
+			http.Error(w, "Unsupported format: "+format, http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 45*time.Second)
+		defer cancel()
+
+		track, err := services.DownloadSubtitles(ctx, services.SubtitleDownloadOptions{
+			URL:                videoURL,
+			Lang:               lang,
+			Auto:               auto,
+			CookiesFromBrowser: detectBrowserFromUA(r.UserAgent()),
+		})
+		if err != nil {
+			logToolUsage(r, queries, "video_subtitles", 0, 0, startTime, err)
+			http.Error(w, fmt.Sprintf("Failed to get subtitles: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// automatic captions repeat each line as they scroll; yt-dlp may
+		// have fallen back to them even when auto was not requested
+		cues := track.Cues
+		if track.Auto {
+			cues = services.CleanSubtitleCues(cues)
+		}
+
+		output, err := services.WriteSubtitles(cues, format)
+		if err != nil {
+			logToolUsage(r, queries, "video_subtitles", 0, 0, startTime, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logToolUsage(r, queries, "video_subtitles", 0, int64(len(output)), startTime, nil)
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"subtitles.%s.%s\"", lang, format))
+		w.Header().Set("Content-Length", strconv.Itoa(len(output)))
+		w.Write(output)
+	}
+}
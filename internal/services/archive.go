@@ -0,0 +1,64 @@
+package services
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ArchiveEntry is a single file to be placed in a ZIP archive
+type ArchiveEntry struct {
+	Name string
+	Data []byte
+}
+
+// WriteZip writes the entries to w as a ZIP archive, in order
+func WriteZip(w io.Writer, entries []ArchiveEntry) error {
+	zw := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		fw, err := zw.Create(entry.Name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to archive: %w", entry.Name, err)
+		}
+
+		if _, err := fw.Write(entry.Data); err != nil {
+			return fmt.Errorf("failed to write %s to archive: %w", entry.Name, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// ArchiveFile is a file on disk to be placed in a ZIP archive
+type ArchiveFile struct {
+	Name string
+	Path string
+}
+
+// WriteZipFiles streams the files to w as a ZIP archive without loading them
+// into memory. Files are stored rather than deflated, since video and audio
+// are already compressed.
+func WriteZipFiles(w io.Writer, files []ArchiveFile) error {
+	zw := zip.NewWriter(w)
+
+	for _, file := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Store})
+		if err != nil {
+			return fmt.Errorf("failed to add %s to archive: %w", file.Name, err)
+		}
+
+		f, err := os.Open(file.Path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", file.Name, err)
+		}
+		_, err = io.Copy(fw, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write %s to archive: %w", file.Name, err)
+		}
+	}
+
+	return zw.Close()
+}
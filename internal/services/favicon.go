@@ -0,0 +1,241 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// faviconSourceSize is the size SVG sources are rendered at before being
+// scaled down to each icon
+const faviconSourceSize = 1024
+
+type FaviconOptions struct {
+	// Name and ShortName go into site.webmanifest
+	Name      string
+	ShortName string
+
+	// ThemeColor colours the browser UI; it defaults to BackgroundColor
+	ThemeColor color.Color
+
+	// BackgroundColor fills the Apple touch icon and the maskable icon,
+	// which must not be transparent; it defaults to white
+	BackgroundColor color.Color
+
+	// MaskablePadding is the margin on each side of the maskable icon as a
+	// percentage of its size. The default of 10 keeps the artwork inside the
+	// 80% safe zone that Android masks may crop to.
+	MaskablePadding int
+}
+
+// FaviconSet holds every generated file plus the HTML that references them
+type FaviconSet struct {
+	Files   []ArchiveEntry
+	Snippet string
+}
+
+// File returns the named file from the set, or nil
+func (s *FaviconSet) File(name string) []byte {
+	for _, f := range s.Files {
+		if f.Name == name {
+			return f.Data
+		}
+	}
+	return nil
+}
+
+type faviconPNG struct {
+	name           string
+	size           int
+	opaque         bool
+	paddingPercent int
+}
+
+// GenerateFaviconSet builds favicon.ico (16, 32 and 48px), PNG favicons,
+// touch and Android icons, a maskable icon and site.webmanifest from one
+// raster image or SVG. Non-square sources are centred on a square canvas.
+func GenerateFaviconSet(input io.Reader, opts FaviconOptions) (*FaviconSet, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	if opts.BackgroundColor == nil {
+		opts.BackgroundColor = color.White
+	}
+	if opts.ThemeColor == nil {
+		opts.ThemeColor = opts.BackgroundColor
+	}
+	if opts.MaskablePadding <= 0 || opts.MaskablePadding > 40 {
+		opts.MaskablePadding = 10
+	}
+	if opts.Name == "" {
+		opts.Name = "My Site"
+	}
+	if opts.ShortName == "" {
+		opts.ShortName = opts.Name
+	}
+
+	src, err := loadFaviconSource(data)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &FaviconSet{}
+
+	var icoImages []image.Image
+	for _, size := range []int{16, 32, 48} {
+		icon, err := drawIcon(src, size, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+		icoImages = append(icoImages, icon)
+	}
+
+	var ico bytes.Buffer
+	if err := EncodeICO(&ico, icoImages); err != nil {
+		return nil, fmt.Errorf("failed to encode favicon.ico: %w", err)
+	}
+	set.Files = append(set.Files, ArchiveEntry{Name: "favicon.ico", Data: ico.Bytes()})
+
+	pngs := []faviconPNG{
+		{name: "favicon-16x16.png", size: 16},
+		{name: "favicon-32x32.png", size: 32},
+		// iOS shows transparent areas as black
+		{name: "apple-touch-icon.png", size: 180, opaque: true},
+		{name: "android-chrome-192x192.png", size: 192},
+		{name: "android-chrome-512x512.png", size: 512},
+		{name: "maskable-icon-512x512.png", size: 512, opaque: true, paddingPercent: opts.MaskablePadding},
+	}
+
+	for _, p := range pngs {
+		var bg color.Color
+		if p.opaque {
+			bg = opts.BackgroundColor
+		}
+
+		icon, err := drawIcon(src, p.size, p.size*p.paddingPercent/100, bg)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, icon); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", p.name, err)
+		}
+		set.Files = append(set.Files, ArchiveEntry{Name: p.name, Data: buf.Bytes()})
+	}
+
+	manifest, err := faviconManifest(opts)
+	if err != nil {
+		return nil, err
+	}
+	set.Files = append(set.Files, ArchiveEntry{Name: "site.webmanifest", Data: manifest})
+
+	set.Snippet = faviconSnippet(hexColor(opts.ThemeColor))
+	set.Files = append(set.Files, ArchiveEntry{Name: "favicon.html", Data: []byte(set.Snippet)})
+
+	return set, nil
+}
+
+// loadFaviconSource decodes the upload through the shared image pipeline,
+// rendering SVGs large enough to be scaled down to every icon size
+func loadFaviconSource(data []byte) (image.Image, error) {
+	if !isSVG(data) {
+		img, _, err := decodeImage(bytes.NewReader(data))
+		return img, err
+	}
+
+	natural, err := RasterizeSVG(data, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if b := natural.Bounds(); b.Dx() >= b.Dy() {
+		return RasterizeSVG(data, faviconSourceSize, 0)
+	}
+	return RasterizeSVG(data, 0, faviconSourceSize)
+}
+
+// drawIcon fits src inside a size×size square less padding on each side,
+// centred over bg (transparent when nil)
+func drawIcon(src image.Image, size, padding int, bg color.Color) (*image.NRGBA, error) {
+	canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+	if bg != nil {
+		draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	}
+
+	inner := size - 2*padding
+	b := src.Bounds()
+	scale := math.Min(float64(inner)/float64(b.Dx()), float64(inner)/float64(b.Dy()))
+	w := max(1, int(math.Round(float64(b.Dx())*scale)))
+	h := max(1, int(math.Round(float64(b.Dy())*scale)))
+
+	scaled, err := scaleImage(src, w, h)
+	if err != nil {
+		return nil, err
+	}
+
+	x := (size - w) / 2
+	y := (size - h) / 2
+	draw.Draw(canvas, image.Rect(x, y, x+w, y+h), scaled, image.Point{}, draw.Over)
+
+	return canvas, nil
+}
+
+func faviconManifest(opts FaviconOptions) ([]byte, error) {
+	type manifestIcon struct {
+		Src     string `json:"src"`
+		Sizes   string `json:"sizes"`
+		Type    string `json:"type"`
+		Purpose string `json:"purpose,omitempty"`
+	}
+
+	manifest := struct {
+		Name            string         `json:"name"`
+		ShortName       string         `json:"short_name"`
+		Icons           []manifestIcon `json:"icons"`
+		ThemeColor      string         `json:"theme_color"`
+		BackgroundColor string         `json:"background_color"`
+		Display         string         `json:"display"`
+	}{
+		Name:      opts.Name,
+		ShortName: opts.ShortName,
+		Icons: []manifestIcon{
+			{Src: "/android-chrome-192x192.png", Sizes: "192x192", Type: "image/png"},
+			{Src: "/android-chrome-512x512.png", Sizes: "512x512", Type: "image/png"},
+			{Src: "/maskable-icon-512x512.png", Sizes: "512x512", Type: "image/png", Purpose: "maskable"},
+		},
+		ThemeColor:      hexColor(opts.ThemeColor),
+		BackgroundColor: hexColor(opts.BackgroundColor),
+		Display:         "standalone",
+	}
+
+	return json.MarshalIndent(manifest, "", "  ")
+}
+
+// faviconSnippet returns the tags to paste into <head>, assuming the files
+// are served from the site root
+func faviconSnippet(themeColor string) string {
+	lines := []string{
+		`<link rel="icon" href="/favicon.ico" sizes="48x48">`,
+		`<link rel="icon" type="image/png" sizes="32x32" href="/favicon-32x32.png">`,
+		`<link rel="icon" type="image/png" sizes="16x16" href="/favicon-16x16.png">`,
+		`<link rel="apple-touch-icon" sizes="180x180" href="/apple-touch-icon.png">`,
+		`<link rel="manifest" href="/site.webmanifest">`,
+		fmt.Sprintf(`<meta name="theme-color" content="%s">`, themeColor),
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func hexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
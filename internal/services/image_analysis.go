@@ -0,0 +1,295 @@
+package services
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// MaxPaletteColors is the most colours a palette may have
+const MaxPaletteColors = 16
+
+// kMeansIterations bounds the refinement passes; palettes seeded from median
+// cut settle well before this
+const kMeansIterations = 12
+
+type ImageAnalysisOptions struct {
+	// Colors in the palette, 6 by default
+	Colors int
+
+	// Method is "kmeans" (the default), which refines a median cut palette
+	// so each colour sits at the centre of the pixels nearest to it, or
+	// plain "median-cut", which is faster but can blend neighbouring colours
+	Method string
+}
+
+// PaletteColor is one colour with the share of the image it covers
+type PaletteColor struct {
+	Hex string `json:"hex"`
+
+	// RGB channels from 0 to 255
+	RGB [3]int `json:"rgb"`
+
+	// HSL as hue in degrees and saturation and lightness in percent
+	HSL [3]float64 `json:"hsl"`
+
+	// Share of the visible pixels, in percent
+	Share float64 `json:"share"`
+}
+
+// ChannelHistogram counts pixels per channel value
+type ChannelHistogram struct {
+	Red   []int `json:"red"`
+	Green []int `json:"green"`
+	Blue  []int `json:"blue"`
+	Alpha []int `json:"alpha"`
+}
+
+type ImageAnalysis struct {
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+
+	Palette []PaletteColor `json:"palette"`
+	Average PaletteColor   `json:"average"`
+
+	Histogram ChannelHistogram `json:"histogram"`
+
+	// HasAlpha is set when any pixel is not fully opaque;
+	// TransparentPercent is the share of fully transparent pixels
+	HasAlpha           bool    `json:"has_alpha"`
+	TransparentPercent float64 `json:"transparent_percent"`
+
+	CSS      string `json:"css"`
+	Tailwind string `json:"tailwind"`
+}
+
+// AnalyzeImage extracts a palette, average colour, per-channel histogram
+// and transparency details from an image. Fully transparent pixels are left
+// out of the palette and average, since they are never seen.
+func AnalyzeImage(input io.Reader, opts ImageAnalysisOptions) (*ImageAnalysis, error) {
+	if opts.Colors == 0 {
+		opts.Colors = 6
+	}
+	if opts.Colors < 1 || opts.Colors > MaxPaletteColors {
+		return nil, fmt.Errorf("palette size must be between 1 and %d", MaxPaletteColors)
+	}
+	if opts.Method == "" {
+		opts.Method = "kmeans"
+	}
+	if opts.Method != "median-cut" && opts.Method != "kmeans" {
+		return nil, fmt.Errorf("unknown palette method: %q", opts.Method)
+	}
+
+	img, format, err := decodeImage(input)
+	if err != nil {
+		return nil, err
+	}
+	src := toNRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	analysis := &ImageAnalysis{
+		Format: format,
+		Width:  w,
+		Height: h,
+		Histogram: ChannelHistogram{
+			Red:   make([]int, 256),
+			Green: make([]int, 256),
+			Blue:  make([]int, 256),
+			Alpha: make([]int, 256),
+		},
+	}
+
+	// averages are weighted by alpha so half transparent pixels count half
+	var sumR, sumG, sumB, sumA float64
+	transparent := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := src.Pix[y*src.Stride+x*4:]
+			analysis.Histogram.Red[p[0]]++
+			analysis.Histogram.Green[p[1]]++
+			analysis.Histogram.Blue[p[2]]++
+			analysis.Histogram.Alpha[p[3]]++
+
+			if p[3] == 0 {
+				transparent++
+				continue
+			}
+			a := float64(p[3])
+			sumR += float64(p[0]) * a
+			sumG += float64(p[1]) * a
+			sumB += float64(p[2]) * a
+			sumA += a
+		}
+	}
+
+	analysis.HasAlpha = analysis.Histogram.Alpha[255] < w*h
+	analysis.TransparentPercent = math.Round(float64(transparent)*10000/float64(w*h)) / 100
+
+	if sumA == 0 {
+		return nil, fmt.Errorf("image is fully transparent")
+	}
+	analysis.Average = newPaletteColor(color.NRGBA{
+		R: uint8(math.Round(sumR / sumA)),
+		G: uint8(math.Round(sumG / sumA)),
+		B: uint8(math.Round(sumB / sumA)),
+		A: 255,
+	}, 100)
+
+	// the palette is searched on opaque colours only, so alpha never
+	// decides where median cut splits
+	var pixels []color.NRGBA
+	for _, p := range samplePixels(src, quantizeSampleLimit) {
+		if p.A > 0 {
+			p.A = 255
+			pixels = append(pixels, p)
+		}
+	}
+
+	boxes := medianCutGroups(pixels, opts.Colors)
+	centres := make([]color.NRGBA, len(boxes))
+	counts := make([]int, len(boxes))
+	for i, box := range boxes {
+		centres[i] = box.average()
+		counts[i] = len(box.pixels)
+	}
+	if opts.Method == "kmeans" {
+		centres, counts = kMeans(pixels, centres)
+	}
+
+	for i, c := range centres {
+		if counts[i] == 0 {
+			continue
+		}
+		share := math.Round(float64(counts[i])*10000/float64(len(pixels))) / 100
+		analysis.Palette = append(analysis.Palette, newPaletteColor(c, share))
+	}
+	slices.SortStableFunc(analysis.Palette, func(a, b PaletteColor) int {
+		return int(math.Round((b.Share - a.Share) * 100))
+	})
+
+	// sampling can miss a few visible pixels in an otherwise empty image
+	if len(analysis.Palette) == 0 {
+		analysis.Palette = []PaletteColor{analysis.Average}
+	}
+
+	analysis.CSS = paletteCSS(analysis.Palette, analysis.Average)
+	analysis.Tailwind = paletteTailwind(analysis.Palette, analysis.Average)
+
+	return analysis, nil
+}
+
+// kMeans moves each centre to the mean of the pixels nearest to it until
+// the assignments stop changing, returning the centres and their sizes
+func kMeans(pixels []color.NRGBA, centres []color.NRGBA) ([]color.NRGBA, []int) {
+	k := len(centres)
+	assigned := make([]int, len(pixels))
+	for i := range assigned {
+		assigned[i] = -1
+	}
+
+	counts := make([]int, k)
+	for iter := 0; iter < kMeansIterations; iter++ {
+		moved := false
+		sums := make([][3]int, k)
+		clear(counts)
+
+		for i, p := range pixels {
+			best, bestDist := 0, math.MaxInt
+			for j, c := range centres {
+				dr, dg, db := int(p.R)-int(c.R), int(p.G)-int(c.G), int(p.B)-int(c.B)
+				if d := dr*dr + dg*dg + db*db; d < bestDist {
+					best, bestDist = j, d
+				}
+			}
+			if assigned[i] != best {
+				assigned[i] = best
+				moved = true
+			}
+			sums[best][0] += int(p.R)
+			sums[best][1] += int(p.G)
+			sums[best][2] += int(p.B)
+			counts[best]++
+		}
+
+		for j := range centres {
+			if n := counts[j]; n > 0 {
+				centres[j] = color.NRGBA{R: uint8(sums[j][0] / n), G: uint8(sums[j][1] / n), B: uint8(sums[j][2] / n), A: 255}
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	return centres, counts
+}
+
+func newPaletteColor(c color.NRGBA, share float64) PaletteColor {
+	h, s, l := rgbToHSL(c.R, c.G, c.B)
+	return PaletteColor{
+		Hex:   hexColor(c),
+		RGB:   [3]int{int(c.R), int(c.G), int(c.B)},
+		HSL:   [3]float64{math.Round(h), math.Round(s * 100), math.Round(l * 100)},
+		Share: share,
+	}
+}
+
+// rgbToHSL returns hue in degrees and saturation and lightness from 0 to 1
+func rgbToHSL(r, g, b uint8) (float64, float64, float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	hi := math.Max(rf, math.Max(gf, bf))
+	lo := math.Min(rf, math.Min(gf, bf))
+	l := (hi + lo) / 2
+
+	if hi == lo {
+		return 0, 0, l
+	}
+
+	d := hi - lo
+	s := d / (1 - math.Abs(2*l-1))
+
+	var h float64
+	switch hi {
+	case rf:
+		h = math.Mod((gf-bf)/d, 6)
+	case gf:
+		h = (bf-rf)/d + 2
+	default:
+		h = (rf-gf)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+
+	return h, s, l
+}
+
+// paletteCSS lists the palette as custom properties, most common first
+func paletteCSS(palette []PaletteColor, average PaletteColor) string {
+	var sb strings.Builder
+	sb.WriteString(":root {\n")
+	for i, c := range palette {
+		fmt.Fprintf(&sb, "  --palette-%d: %s;\n", i+1, c.Hex)
+	}
+	fmt.Fprintf(&sb, "  --palette-average: %s;\n", average.Hex)
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// paletteTailwind is a tailwind.config.js fragment giving classes such as
+// bg-palette-1 and text-palette-average
+func paletteTailwind(palette []PaletteColor, average PaletteColor) string {
+	var sb strings.Builder
+	sb.WriteString("module.exports = {\n  theme: {\n    extend: {\n      colors: {\n        palette: {\n")
+	for i, c := range palette {
+		fmt.Fprintf(&sb, "          %d: '%s',\n", i+1, c.Hex)
+	}
+	fmt.Fprintf(&sb, "          average: '%s',\n", average.Hex)
+	sb.WriteString("        },\n      },\n    },\n  },\n}\n")
+	return sb.String()
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxBatchImages caps the files converted in one batch, counting the
+	// contents of uploaded ZIPs
+	MaxBatchImages = 100
+
+	// maxBatchEntrySize caps a single image extracted from an uploaded ZIP
+	maxBatchEntrySize = 25 << 20
+
+	// maxBatchArchiveSize caps the total extracted from uploaded ZIPs
+	maxBatchArchiveSize = 200 << 20
+
+	maxImageBatchWorkers = 4
+)
+
+// BatchImageInput is one file of a batch conversion
+type BatchImageInput struct {
+	Name string
+	Data []byte
+}
+
+// BatchImageResult is the outcome for one input. Failed files have Error
+// set and no Data, so one bad file does not fail the whole batch.
+type BatchImageResult struct {
+	Name       string `json:"name"`
+	OutputName string `json:"output,omitempty"`
+	InputSize  int64  `json:"input_size"`
+	OutputSize int64  `json:"output_size,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Data       []byte `json:"-"`
+}
+
+// ExpandBatchUploads returns the uploaded files with any ZIP archives
+// replaced by the files inside them. Directories, hidden files and macOS
+// resource forks in archives are skipped.
+func ExpandBatchUploads(uploads []BatchImageInput) ([]BatchImageInput, error) {
+	var inputs []BatchImageInput
+	var extracted int64
+
+	for _, upload := range uploads {
+		if !bytes.HasPrefix(upload.Data, []byte("PK\x03\x04")) {
+			inputs = append(inputs, upload)
+			continue
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(upload.Data), int64(len(upload.Data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", upload.Name, err)
+		}
+
+		for _, f := range zr.File {
+			base := path.Base(f.Name)
+			if f.FileInfo().IsDir() || strings.HasPrefix(base, ".") || strings.HasPrefix(f.Name, "__MACOSX/") {
+				continue
+			}
+			if len(inputs) >= MaxBatchImages {
+				return nil, fmt.Errorf("a batch can hold at most %d images", MaxBatchImages)
+			}
+
+			data, err := readZipEntry(f, maxBatchEntrySize)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			extracted += int64(len(data))
+			if extracted > maxBatchArchiveSize {
+				return nil, fmt.Errorf("archives extract to more than %d MB", maxBatchArchiveSize>>20)
+			}
+
+			inputs = append(inputs, BatchImageInput{Name: f.Name, Data: data})
+		}
+	}
+
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no images found in the upload")
+	}
+	if len(inputs) > MaxBatchImages {
+		return nil, fmt.Errorf("a batch can hold at most %d images", MaxBatchImages)
+	}
+
+	return inputs, nil
+}
+
+// readZipEntry reads at most limit bytes, since the sizes in the central
+// directory are not trustworthy
+func readZipEntry(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("file is larger than %d MB", limit>>20)
+	}
+	return data, nil
+}
+
+// ConvertImageBatch converts every input with the same options on a bounded
+// pool of workers and calls emit once per input, in input order. Output
+// names keep the input's base name with the new extension and are made
+// unique within the batch.
+func ConvertImageBatch(ctx context.Context, inputs []BatchImageInput, opts ImageConvertOptions, emit func(BatchImageResult) error) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("no images to convert")
+	}
+
+	_, ext := ImageContentType(strings.ToLower(opts.OutputFormat))
+	names := batchOutputNames(inputs, ext)
+
+	type job struct {
+		result BatchImageResult
+		done   chan struct{}
+	}
+
+	jobs := make([]*job, len(inputs))
+	for i, input := range inputs {
+		jobs[i] = &job{
+			result: BatchImageResult{Name: input.Name, InputSize: int64(len(input.Data))},
+			done:   make(chan struct{}),
+		}
+	}
+
+	workers := min(runtime.NumCPU(), maxImageBatchWorkers, len(inputs))
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// ahead limits how many converted images may wait in memory for emit
+	ahead := make(chan struct{}, workers*2)
+	queue := make(chan int)
+
+	go func() {
+		defer close(queue)
+		for i := range inputs {
+			select {
+			case ahead <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case queue <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				var buf bytes.Buffer
+				if err := ConvertImage(bytes.NewReader(inputs[i].Data), &buf, opts); err != nil {
+					jobs[i].result.Error = err.Error()
+				} else {
+					jobs[i].result.OutputName = names[i]
+					jobs[i].result.OutputSize = int64(buf.Len())
+					jobs[i].result.Data = buf.Bytes()
+				}
+				close(jobs[i].done)
+			}
+		}()
+	}
+
+	for _, j := range jobs {
+		select {
+		case <-j.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if err := emit(j.result); err != nil {
+			return err
+		}
+		j.result.Data = nil
+		<-ahead
+	}
+
+	return nil
+}
+
+// batchOutputNames swaps each input's extension for ext, numbering
+// duplicates so archive entries never collide
+func batchOutputNames(inputs []BatchImageInput, ext string) []string {
+	names := make([]string, len(inputs))
+	used := make(map[string]bool)
+
+	for i, input := range inputs {
+		base := path.Base(strings.ReplaceAll(input.Name, "\\", "/"))
+		base = strings.TrimSuffix(base, path.Ext(base))
+		if base == "" || base == "." || base == "/" {
+			base = fmt.Sprintf("image-%d", i+1)
+		}
+
+		name := base + "." + ext
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d.%s", base, n, ext)
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+
+	return names
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+)
+
+const (
+	minCompressQuality = 10
+	maxCompressQuality = 95
+
+	// each downscale step keeps this fraction of the width and height
+	compressDownscaleStep = 0.85
+	maxCompressDownscales = 12
+)
+
+type ImageCompressOptions struct {
+	// Format of the result: jpeg, webp or png. Empty keeps the input format
+	// where possible.
+	Format string
+
+	// TargetKB is the size to aim for; 0 means just apply Quality
+	TargetKB int
+
+	// Quality is used for JPEG/WebP when there is no target
+	Quality int
+
+	// MaxDimension limits the longest side in pixels; 0 keeps the size
+	MaxDimension int
+
+	// AllowDownscale lets the compressor shrink the image further when
+	// the lowest quality still misses the target
+	AllowDownscale bool
+
+	// Colors reduces PNG output to a palette of this many colours;
+	// 0 keeps full colour
+	Colors int
+}
+
+type ImageCompressResult struct {
+	Data           []byte `json:"data"`
+	Format         string `json:"format"`
+	OriginalSize   int64  `json:"original_size"`
+	CompressedSize int64  `json:"compressed_size"`
+	Quality        int    `json:"quality,omitempty"`
+	Colors         int    `json:"colors,omitempty"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	TargetMet      bool   `json:"target_met"`
+}
+
+// CompressImage re-encodes the image as small as possible within opts.
+// With a target size, JPEG/WebP quality is binary searched for the highest
+// setting that fits and PNG palettes are halved until it fits. If that is
+// not enough and AllowDownscale is set the image is shrunk step by step. The
+// smallest attempt is returned even when the target could not be reached.
+func CompressImage(input io.Reader, opts ImageCompressOptions) (*ImageCompressResult, error) {
+	var original bytes.Buffer
+	img, inputFormat, err := decodeImage(io.TeeReader(input, &original))
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = inputFormat
+		// formats we can't optimise are compressed as PNG
+		if !contains([]string{"jpeg", "webp", "png"}, format) {
+			format = "png"
+		}
+	}
+	switch format {
+	case "jpg":
+		format = "jpeg"
+	case "jpeg", "webp", "png":
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", opts.Format)
+	}
+
+	if opts.Quality < 1 || opts.Quality > 100 {
+		opts.Quality = 80
+	}
+	if opts.Colors != 0 && (opts.Colors < 2 || opts.Colors > 256) {
+		return nil, fmt.Errorf("palette size must be between 2 and 256 colours")
+	}
+	if opts.TargetKB < 0 || opts.MaxDimension < 0 {
+		return nil, fmt.Errorf("target size and max dimension cannot be negative")
+	}
+
+	if opts.MaxDimension > 0 {
+		b := img.Bounds()
+		if b.Dx() > opts.MaxDimension || b.Dy() > opts.MaxDimension {
+			img, err = ApplyImageOperations(img, []ImageOperation{{
+				Op:     "resize",
+				Mode:   "fit",
+				Width:  opts.MaxDimension,
+				Height: opts.MaxDimension,
+			}})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	target := int64(opts.TargetKB) * 1024
+
+	var best *ImageCompressResult
+	for step := 0; step <= maxCompressDownscales; step++ {
+		result, err := compressAtSize(img, format, target, opts)
+		if err != nil {
+			return nil, err
+		}
+		if best == nil || result.CompressedSize < best.CompressedSize {
+			best = result
+		}
+
+		if target == 0 || result.TargetMet || !opts.AllowDownscale {
+			break
+		}
+
+		b := img.Bounds()
+		w := int(math.Round(float64(b.Dx()) * compressDownscaleStep))
+		h := int(math.Round(float64(b.Dy()) * compressDownscaleStep))
+		if w < 16 || h < 16 {
+			break
+		}
+
+		img, err = scaleImage(img, w, h)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	best.OriginalSize = int64(original.Len())
+	return best, nil
+}
+
+// compressAtSize finds the best encoding of img at its current dimensions
+func compressAtSize(img image.Image, format string, target int64, opts ImageCompressOptions) (*ImageCompressResult, error) {
+	b := img.Bounds()
+	result := &ImageCompressResult{
+		Format: format,
+		Width:  b.Dx(),
+		Height: b.Dy(),
+	}
+
+	if format == "png" {
+		return compressPNG(img, target, opts.Colors, result)
+	}
+
+	encode := func(quality int) ([]byte, error) {
+		var buf bytes.Buffer
+		if err := encodeImage(&buf, img, format, quality); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	if target == 0 {
+		data, err := encode(opts.Quality)
+		if err != nil {
+			return nil, err
+		}
+		result.Data, result.Quality, result.TargetMet = data, opts.Quality, true
+		result.CompressedSize = int64(len(data))
+		return result, nil
+	}
+
+	// size grows with quality, so look for the highest quality that fits
+	lo, hi := minCompressQuality, maxCompressQuality
+	for lo <= hi {
+		quality := (lo + hi) / 2
+		data, err := encode(quality)
+		if err != nil {
+			return nil, err
+		}
+
+		fits := int64(len(data)) <= target
+		if fits {
+			lo = quality + 1
+		} else {
+			hi = quality - 1
+		}
+
+		// keep the best fitting attempt, or the smallest one if none fit
+		if (fits && (!result.TargetMet || quality > result.Quality)) ||
+			(!fits && !result.TargetMet && (result.Data == nil || len(data) < len(result.Data))) {
+			result.Data, result.Quality, result.TargetMet = data, quality, fits
+		}
+	}
+
+	result.CompressedSize = int64(len(result.Data))
+	return result, nil
+}
+
+// compressPNG encodes losslessly, or with a palette when colors is set. With
+// a target the palette is halved until the image fits.
+func compressPNG(img image.Image, target int64, colors int, result *ImageCompressResult) (*ImageCompressResult, error) {
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+
+	encode := func(colors int) ([]byte, error) {
+		var src image.Image = img
+		if colors > 0 {
+			src = QuantizeImage(img, colors)
+		}
+
+		var buf bytes.Buffer
+		if err := encoder.Encode(&buf, src); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	data, err := encode(colors)
+	if err != nil {
+		return nil, err
+	}
+	result.Data, result.Colors = data, colors
+
+	if target > 0 && int64(len(data)) > target {
+		next := 256
+		if colors > 0 {
+			next = colors / 2
+		}
+
+		for ; next >= 2 && int64(len(result.Data)) > target; next /= 2 {
+			data, err := encode(next)
+			if err != nil {
+				return nil, err
+			}
+			if len(data) < len(result.Data) {
+				result.Data, result.Colors = data, next
+			}
+		}
+	}
+
+	result.CompressedSize = int64(len(result.Data))
+	result.TargetMet = target == 0 || result.CompressedSize <= target
+	return result, nil
+}
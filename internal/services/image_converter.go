@@ -1,6 +1,8 @@
 package services
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
@@ -9,34 +11,91 @@ import (
 	"strings"
 
 	"github.com/chai2010/webp"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
 )
 
 type ImageConvertOptions struct {
 	OutputFormat string
 	Quality      int
+
+	// Operations are applied in order before encoding
+	Operations []ImageOperation
+
+	// Frame picks the 1-based frame of an animated GIF to export when the
+	// output is a still format; 0 means the first frame. GIF to GIF keeps
+	// every frame.
+	Frame int
+
+	// Watermark is stamped over the image after Operations
+	Watermark *Watermark
 }
 
 // use streaming to avoid loading the entire image into memory multiple times
 func ConvertImage(input io.Reader, output io.Writer, opts ImageConvertOptions) error {
-	img, format, err := image.Decode(input)
-	if err != nil {
-		return fmt.Errorf("failed to decode image: %w", err)
+	format := strings.ToLower(opts.OutputFormat)
+	if format == "jpg" {
+		format = "jpeg"
 	}
-
-	// validate we support the input format
-	supportedInputs := []string{"jpeg", "png", "webp"}
-	if !contains(supportedInputs, strings.ToLower(format)) {
-		return fmt.Errorf("unsupported input format: %s", format)
+	if !contains(ImageFormats, format) {
+		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
 	}
 
 	if opts.Quality < 1 || opts.Quality > 100 {
 		opts.Quality = 85
 	}
 
-	switch strings.ToLower(opts.OutputFormat) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+
+	watermark, err := newWatermarker(opts.Watermark)
+	if err != nil {
+		return err
+	}
+
+	// edit runs the operations and the watermark, once per frame for GIFs
+	edit := func(img image.Image) (image.Image, error) {
+		img, err := ApplyImageOperations(img, opts.Operations)
+		if err != nil || watermark == nil {
+			return img, err
+		}
+		return watermark.apply(img)
+	}
+
+	var img image.Image
+	if DetectImageFormat(data) == "gif" {
+		anim, err := decodeGIFAnimation(data)
+		if err != nil {
+			return err
+		}
+		if format == "gif" {
+			return anim.encode(output, edit)
+		}
+		if img, err = anim.frame(opts.Frame); err != nil {
+			return err
+		}
+	} else {
+		if img, _, err = decodeImage(bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+
+	img, err = edit(img)
+	if err != nil {
+		return err
+	}
+
+	return encodeImage(output, img, format, opts.Quality)
+}
+
+// encodeImage is the shared encoding path, the counterpart of decodeImage
+func encodeImage(output io.Writer, img image.Image, format string, quality int) error {
+	switch strings.ToLower(format) {
 	case "jpeg", "jpg":
 		return jpeg.Encode(output, img, &jpeg.Options{
-			Quality: opts.Quality,
+			Quality: quality,
 		})
 
 	case "png":
@@ -47,12 +106,83 @@ func ConvertImage(input io.Reader, output io.Writer, opts ImageConvertOptions) e
 
 	case "webp":
 		return webp.Encode(output, img, &webp.Options{
-			Quality: float32(opts.Quality),
+			Quality: float32(quality),
 		})
 
+	case "gif":
+		return encodeGIF(output, img)
+
+	case "bmp":
+		return bmp.Encode(output, img)
+
+	case "tiff", "tif":
+		return tiff.Encode(output, img, &tiff.Options{
+			Compression: tiff.Deflate,
+		})
+
+	case "ico":
+		// icons are at most 256px, larger images are scaled to fit
+		b := img.Bounds()
+		if b.Dx() > MaxICOSize || b.Dy() > MaxICOSize {
+			var err error
+			img, err = resizeImage(img, ImageOperation{Mode: "fit", Width: MaxICOSize, Height: MaxICOSize})
+			if err != nil {
+				return err
+			}
+		}
+		return EncodeICO(output, []image.Image{img})
+
+	case "avif":
+		return encodeAVIF(output, img, quality)
+
 	default:
-		return fmt.Errorf("unsupported output format: %s", opts.OutputFormat)
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// decodeImage is the shared decoding path for every tool that accepts
+// uploaded images. The format is detected from the file's magic bytes, the
+// header is checked against DefaultImageLimits before any pixels are decoded
+// and images with an EXIF Orientation are returned upright.
+func decodeImage(input io.Reader) (image.Image, string, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// SVGs are drawn at their own size; RasterizeSVG applies the limits
+	if isSVG(data) {
+		img, err := RasterizeSVG(data, 0, 0)
+		return img, "svg", err
+	}
+
+	format := DetectImageFormat(data)
+	if format == "" {
+		return nil, "", fmt.Errorf("unsupported input format")
 	}
+
+	if err := checkImageLimits(data, format); err != nil {
+		return nil, "", err
+	}
+
+	img, err := decodeImageData(data, format)
+	if err != nil {
+		if errors.Is(err, ErrAVIFUnavailable) || errors.Is(err, ErrImageTooLarge) {
+			return nil, "", err
+		}
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if segments := extractImageSegments(data); len(segments.exif) > 0 {
+		if tags, err := parseEXIF(segments.exif); err == nil {
+			img, err = applyOrientation(img, tags.orientation())
+			if err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	return img, format, nil
 }
 
 func contains(slice []string, item string) bool {
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"slices"
+
+	"golang.org/x/image/draw"
+)
+
+// diffCellSize is the grid used to group changed pixels into regions;
+// changes within a cell or two of each other end up in the same box
+const diffCellSize = 8
+
+// MaxDiffRegions caps the boxes reported and drawn, keeping the largest
+const MaxDiffRegions = 50
+
+// maxPSNR stands in for the infinite PSNR of identical images, which JSON
+// cannot represent
+const maxPSNR = 100
+
+type ImageDiffOptions struct {
+	// Threshold is the largest per-channel difference (0-255) still treated
+	// as unchanged, to ignore compression noise. 0 flags any difference.
+	Threshold int
+
+	// Align is how differently sized images are matched up: "resize" (the
+	// default) scales the second image to the first, "pad" compares them
+	// from the top-left corner and counts the uncovered area as changed.
+	Align string
+}
+
+// DiffRegion is a bounding box around a group of changed pixels
+type DiffRegion struct {
+	X       int `json:"x"`
+	Y       int `json:"y"`
+	Width   int `json:"width"`
+	Height  int `json:"height"`
+	Changed int `json:"changed_pixels"`
+}
+
+type ImageDiffResult struct {
+	Width          int          `json:"width"`
+	Height         int          `json:"height"`
+	Resized        bool         `json:"resized"`
+	ChangedPixels  int          `json:"changed_pixels"`
+	ChangedPercent float64      `json:"changed_percent"`
+	PSNR           float64      `json:"psnr"`
+	SSIM           float64      `json:"ssim"`
+	Regions        []DiffRegion `json:"regions"`
+	TotalRegions   int          `json:"total_regions"`
+
+	// Diff is a PNG of the first image faded out, with changed pixels in red
+	// and each region outlined
+	Diff []byte `json:"-"`
+}
+
+// CompareImages decodes two images through the shared pipeline and reports
+// how they differ. Transparent pixels are compared as if shown over white.
+// PSNR is capped at 100 dB for identical images; SSIM is computed on luma.
+func CompareImages(before, after io.Reader, opts ImageDiffOptions) (*ImageDiffResult, error) {
+	if opts.Threshold < 0 || opts.Threshold > 255 {
+		return nil, fmt.Errorf("threshold must be between 0 and 255")
+	}
+	if opts.Align == "" {
+		opts.Align = "resize"
+	}
+	if opts.Align != "resize" && opts.Align != "pad" {
+		return nil, fmt.Errorf("unknown alignment: %q", opts.Align)
+	}
+
+	imgA, _, err := decodeImage(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode first image: %w", err)
+	}
+	imgB, _, err := decodeImage(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode second image: %w", err)
+	}
+
+	result := &ImageDiffResult{}
+
+	sizeA, sizeB := imgA.Bounds().Size(), imgB.Bounds().Size()
+	if sizeA != sizeB && opts.Align == "resize" {
+		if imgB, err = scaleImage(imgB, sizeA.X, sizeA.Y); err != nil {
+			return nil, err
+		}
+		result.Resized = true
+		sizeB = sizeA
+	}
+
+	w, h := max(sizeA.X, sizeB.X), max(sizeA.Y, sizeB.Y)
+	result.Width, result.Height = w, h
+
+	// padding can make the canvas far larger than either image. Each pixel
+	// needs two flattened NRGBA copies, two float64 lumas, the mask and the
+	// diff image.
+	if err := checkImageDimensions(w, h); err != nil {
+		return nil, err
+	}
+	const diffBytesPerPixel = 2*4 + 2*8 + 1 + 4
+	if need := int64(w) * int64(h) * diffBytesPerPixel; need > DefaultImageLimits.MaxDecodeBytes {
+		return nil, fmt.Errorf("%w: comparing at %dx%d needs about %d MB, the limit is %d MB",
+			ErrImageTooLarge, w, h, need>>20, DefaultImageLimits.MaxDecodeBytes>>20)
+	}
+
+	a := flattenForDiff(imgA, w, h)
+	b := flattenForDiff(imgB, w, h)
+
+	// pixels only one of the images covers always count as changed
+	covered := image.Rect(0, 0, min(sizeA.X, sizeB.X), min(sizeA.Y, sizeB.Y))
+
+	mask := make([]bool, w*h)
+	var sqErr float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pa := a.Pix[y*a.Stride+x*4:]
+			pb := b.Pix[y*b.Stride+x*4:]
+
+			delta := 0
+			for c := 0; c < 3; c++ {
+				d := int(pa[c]) - int(pb[c])
+				sqErr += float64(d * d)
+				delta = max(delta, d, -d)
+			}
+
+			if delta > opts.Threshold || !image.Pt(x, y).In(covered) {
+				mask[y*w+x] = true
+				result.ChangedPixels++
+			}
+		}
+	}
+
+	total := w * h
+	result.ChangedPercent = math.Round(float64(result.ChangedPixels)*10000/float64(total)) / 100
+
+	result.PSNR = maxPSNR
+	if mse := sqErr / float64(total*3); mse > 0 {
+		result.PSNR = math.Min(maxPSNR, math.Round(10*math.Log10(255*255/mse)*100)/100)
+	}
+	result.SSIM = math.Round(ssim(a, b)*10000) / 10000
+
+	regions := diffRegions(mask, w, h)
+	result.TotalRegions = len(regions)
+	if len(regions) > MaxDiffRegions {
+		regions = regions[:MaxDiffRegions]
+	}
+	result.Regions = regions
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, drawDiff(a, mask, regions)); err != nil {
+		return nil, fmt.Errorf("failed to encode diff image: %w", err)
+	}
+	result.Diff = buf.Bytes()
+
+	return result, nil
+}
+
+// flattenForDiff draws img over white on a w×h canvas
+func flattenForDiff(img image.Image, w, h int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
+
+// ssim returns the mean structural similarity of the luma of a and b over
+// 8×8 windows moved 4 pixels at a time
+func ssim(a, b *image.NRGBA) float64 {
+	const window, step = 8, 4
+	const c1, c2 = (0.01 * 255) * (0.01 * 255), (0.03 * 255) * (0.03 * 255)
+
+	w, h := a.Bounds().Dx(), a.Bounds().Dy()
+	la, lb := luma(a), luma(b)
+
+	// images smaller than a window are compared as one window
+	ww, wh := min(window, w), min(window, h)
+	n := float64(ww * wh)
+
+	var sum float64
+	var count int
+	for y := 0; y+wh <= h; y += step {
+		for x := 0; x+ww <= w; x += step {
+			var sa, sb, saa, sbb, sab float64
+			for j := y; j < y+wh; j++ {
+				for i := x; i < x+ww; i++ {
+					va, vb := la[j*w+i], lb[j*w+i]
+					sa += va
+					sb += vb
+					saa += va * va
+					sbb += vb * vb
+					sab += va * vb
+				}
+			}
+
+			ma, mb := sa/n, sb/n
+			va := saa/n - ma*ma
+			vb := sbb/n - mb*mb
+			cov := sab/n - ma*mb
+
+			sum += ((2*ma*mb + c1) * (2*cov + c2)) / ((ma*ma + mb*mb + c1) * (va + vb + c2))
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 1
+	}
+	return sum / float64(count)
+}
+
+// luma converts to Rec. 601 luma, the usual input to SSIM
+func luma(img *image.NRGBA) []float64 {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	out := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := img.Pix[y*img.Stride+x*4:]
+			out[y*w+x] = 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+		}
+	}
+	return out
+}
+
+// diffRegions groups changed pixels by flood filling a coarse grid of
+// cells, joining cells up to one empty cell apart, and returns the tight
+// bounding box of each group, largest first
+func diffRegions(mask []bool, w, h int) []DiffRegion {
+	cols := (w + diffCellSize - 1) / diffCellSize
+	rows := (h + diffCellSize - 1) / diffCellSize
+
+	type cell struct {
+		bounds  image.Rectangle
+		changed int
+	}
+	cells := make([]cell, cols*rows)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if !mask[y*w+x] {
+				continue
+			}
+			c := &cells[(y/diffCellSize)*cols+x/diffCellSize]
+			c.bounds = c.bounds.Union(image.Rect(x, y, x+1, y+1))
+			c.changed++
+		}
+	}
+
+	seen := make([]bool, len(cells))
+	regions := []DiffRegion{}
+	for start := range cells {
+		if seen[start] || cells[start].changed == 0 {
+			continue
+		}
+
+		var bounds image.Rectangle
+		changed := 0
+		queue := []int{start}
+		seen[start] = true
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			bounds = bounds.Union(cells[i].bounds)
+			changed += cells[i].changed
+
+			cx, cy := i%cols, i/cols
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					nx, ny := cx+dx, cy+dy
+					if nx < 0 || ny < 0 || nx >= cols || ny >= rows {
+						continue
+					}
+					j := ny*cols + nx
+					if !seen[j] && cells[j].changed > 0 {
+						seen[j] = true
+						queue = append(queue, j)
+					}
+				}
+			}
+		}
+
+		regions = append(regions, DiffRegion{
+			X:       bounds.Min.X,
+			Y:       bounds.Min.Y,
+			Width:   bounds.Dx(),
+			Height:  bounds.Dy(),
+			Changed: changed,
+		})
+	}
+
+	slices.SortStableFunc(regions, func(a, b DiffRegion) int {
+		return b.Width*b.Height - a.Width*a.Height
+	})
+	return regions
+}
+
+// drawDiff fades base towards white, paints changed pixels red and outlines
+// every region
+func drawDiff(base *image.NRGBA, mask []bool, regions []DiffRegion) *image.NRGBA {
+	w, h := base.Bounds().Dx(), base.Bounds().Dy()
+	out := image.NewNRGBA(base.Bounds())
+
+	changed := color.NRGBA{R: 255, A: 255}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*out.Stride + x*4
+			if mask[y*w+x] {
+				copy(out.Pix[i:i+4], []uint8{changed.R, changed.G, changed.B, changed.A})
+				continue
+			}
+			p := base.Pix[y*base.Stride+x*4:]
+			gray := uint8((299*int(p[0]) + 587*int(p[1]) + 114*int(p[2])) / 1000)
+			faded := 255 - (255-gray)/4
+			copy(out.Pix[i:i+4], []uint8{faded, faded, faded, 255})
+		}
+	}
+
+	box := &image.Uniform{C: color.NRGBA{R: 255, G: 0, B: 255, A: 255}}
+	stroke := max(1, min(w, h)/400)
+	for _, r := range regions {
+		rect := image.Rect(r.X, r.Y, r.X+r.Width, r.Y+r.Height).Inset(-stroke - 1).Intersect(out.Bounds())
+		for _, edge := range []image.Rectangle{
+			image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+stroke),
+			image.Rect(rect.Min.X, rect.Max.Y-stroke, rect.Max.X, rect.Max.Y),
+			image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+stroke, rect.Max.Y),
+			image.Rect(rect.Max.X-stroke, rect.Min.Y, rect.Max.X, rect.Max.Y),
+		} {
+			draw.Draw(out, edge, box, image.Point{}, draw.Src)
+		}
+	}
+
+	return out
+}
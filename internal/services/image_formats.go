@@ -0,0 +1,240 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// ErrAVIFUnavailable is returned when AVIF is requested but libavif's
+// command line tools are not installed
+var ErrAVIFUnavailable = errors.New("AVIF support requires avifenc and avifdec (install with: apt-get install libavif-bin)")
+
+// avifTimeout bounds a single avifenc/avifdec run
+const avifTimeout = 2 * time.Minute
+
+// ImageFormats lists every format ConvertImage can read and write
+var ImageFormats = []string{"jpeg", "png", "webp", "gif", "bmp", "tiff", "ico", "avif"}
+
+// DetectImageFormat identifies the image from its leading bytes rather than
+// trusting the file name or the registered decoders. It returns "" for
+// anything it does not recognise.
+func DetectImageFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	case bytes.HasPrefix(data, pngSignature):
+		return "png"
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return "gif"
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return "webp"
+	case bytes.HasPrefix(data, []byte("BM")) && len(data) >= 26:
+		return "bmp"
+	case bytes.HasPrefix(data, []byte("II*\x00")), bytes.HasPrefix(data, []byte("MM\x00*")):
+		return "tiff"
+	case bytes.HasPrefix(data, []byte{0, 0, 1, 0}) && len(data) >= 22:
+		return "ico"
+	case isAVIF(data):
+		return "avif"
+	}
+	return ""
+}
+
+// isAVIF checks the ISO-BMFF ftyp box for an AVIF brand
+func isAVIF(data []byte) bool {
+	if len(data) < 16 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+
+	size := int(data[0])<<24 | int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+	if size < 16 || size > len(data) {
+		size = min(len(data), 64)
+	}
+
+	// major brand, then compatible brands after the minor version
+	for pos := 8; pos+4 <= size; pos += 4 {
+		if pos == 12 {
+			continue
+		}
+		if brand := string(data[pos : pos+4]); brand == "avif" || brand == "avis" {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageContentType returns the MIME type and file extension for a format
+func ImageContentType(format string) (string, string) {
+	switch format {
+	case "jpeg", "jpg":
+		return "image/jpeg", "jpg"
+	case "png":
+		return "image/png", "png"
+	case "webp":
+		return "image/webp", "webp"
+	case "gif":
+		return "image/gif", "gif"
+	case "bmp":
+		return "image/bmp", "bmp"
+	case "tiff", "tif":
+		return "image/tiff", "tiff"
+	case "ico":
+		return "image/x-icon", "ico"
+	case "avif":
+		return "image/avif", "avif"
+	}
+	return "application/octet-stream", "bin"
+}
+
+// decodeImageData decodes data already identified by DetectImageFormat.
+// Animated GIFs yield their first frame.
+func decodeImageData(data []byte, format string) (image.Image, error) {
+	r := bytes.NewReader(data)
+
+	switch format {
+	case "jpeg":
+		return jpeg.Decode(r)
+	case "png":
+		return png.Decode(r)
+	case "webp":
+		return webp.Decode(r)
+	case "gif":
+		anim, err := decodeGIFAnimation(data)
+		if err != nil {
+			return nil, err
+		}
+		return anim.frame(1)
+	case "bmp":
+		return bmp.Decode(r)
+	case "tiff":
+		return tiff.Decode(r)
+	case "ico":
+		return decodeICO(data)
+	case "avif":
+		return decodeAVIF(data)
+	}
+
+	return nil, fmt.Errorf("unsupported input format")
+}
+
+// decodeImageConfig reads the dimensions without decoding pixels where the
+// format allows it
+func decodeImageConfig(data []byte, format string) (image.Config, error) {
+	switch format {
+	case "ico":
+		return decodeICOConfig(data)
+	case "avif":
+		img, err := decodeImageData(data, format)
+		if err != nil {
+			return image.Config{}, err
+		}
+		return image.Config{ColorModel: img.ColorModel(), Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}, nil
+	case "webp":
+		return webp.DecodeConfig(bytes.NewReader(data))
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	return config, err
+}
+
+// encodeAVIF shells out to avifenc, going through a lossless PNG
+func encodeAVIF(w io.Writer, img image.Image, quality int) error {
+	avifenc, err := exec.LookPath("avifenc")
+	if err != nil {
+		return ErrAVIFUnavailable
+	}
+
+	tmpDir, err := os.MkdirTemp("", "avif-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inputPath := filepath.Join(tmpDir, "input.png")
+	outputPath := filepath.Join(tmpDir, "output.avif")
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	if err := os.WriteFile(inputPath, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), avifTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, avifenc, "-q", strconv.Itoa(quality), "-s", "6", inputPath, outputPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("avifenc failed: %w\nOutput: %s", err, string(output))
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read AVIF output: %w", err)
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// decodeAVIF shells out to avifdec and reads back a PNG
+func decodeAVIF(data []byte) (image.Image, error) {
+	avifdec, err := exec.LookPath("avifdec")
+	if err != nil {
+		return nil, ErrAVIFUnavailable
+	}
+
+	tmpDir, err := os.MkdirTemp("", "avif-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inputPath := filepath.Join(tmpDir, "input.avif")
+	outputPath := filepath.Join(tmpDir, "output.png")
+
+	if err := os.WriteFile(inputPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), avifTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, avifdec, inputPath, outputPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("avifdec failed: %w\nOutput: %s", err, string(output))
+	}
+
+	decoded, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decoded AVIF: %w", err)
+	}
+
+	if err := checkImageLimits(decoded, "png"); err != nil {
+		return nil, err
+	}
+
+	return png.Decode(bytes.NewReader(decoded))
+}
+
+// encodeGIF writes a single frame GIF
+func encodeGIF(w io.Writer, img image.Image) error {
+	return gif.Encode(w, quantizeForGIF(img), nil)
+}
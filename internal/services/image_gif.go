@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+// gifAnimation holds fully composited frames, so each one can be edited or
+// exported on its own regardless of how the source GIF was optimised
+type gifAnimation struct {
+	frames    []*image.NRGBA
+	delays    []int
+	loopCount int
+}
+
+func decodeGIFAnimation(data []byte) (*gifAnimation, error) {
+	if err := checkImageLimits(data, "gif"); err != nil {
+		return nil, err
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GIF: %w", err)
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() && len(g.Image) > 0 {
+		bounds = g.Image[0].Bounds()
+	}
+
+	anim := &gifAnimation{loopCount: g.LoopCount}
+	canvas := image.NewNRGBA(bounds)
+
+	for i, frame := range g.Image {
+		var previous *image.NRGBA
+		if i < len(g.Disposal) && g.Disposal[i] == gif.DisposalPrevious {
+			previous = cloneNRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		anim.frames = append(anim.frames, cloneNRGBA(canvas))
+		anim.delays = append(anim.delays, g.Delay[i])
+
+		if i < len(g.Disposal) {
+			switch g.Disposal[i] {
+			case gif.DisposalBackground:
+				draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				canvas = previous
+			}
+		}
+	}
+
+	if len(anim.frames) == 0 {
+		return nil, fmt.Errorf("GIF has no frames")
+	}
+
+	return anim, nil
+}
+
+// frame returns the 1-based frame n, or the first frame for 0
+func (a *gifAnimation) frame(n int) (image.Image, error) {
+	if n == 0 {
+		n = 1
+	}
+	if n < 1 || n > len(a.frames) {
+		return nil, fmt.Errorf("frame %d does not exist, the GIF has %d frames", n, len(a.frames))
+	}
+	return a.frames[n-1], nil
+}
+
+// encode writes the animation back out after passing every frame through
+// edit, quantizing each frame on its own so it keeps the best palette for
+// its content
+func (a *gifAnimation) encode(w io.Writer, edit func(image.Image) (image.Image, error)) error {
+	out := &gif.GIF{LoopCount: a.loopCount}
+
+	// every edited frame is kept until the GIF is written, so the first
+	// frame's output size decides whether the whole animation fits
+	first, err := edit(a.frames[0])
+	if err != nil {
+		return err
+	}
+	b := first.Bounds()
+	// paletted frames hold one byte per pixel
+	if total := int64(len(a.frames)) * int64(b.Dx()) * int64(b.Dy()); total > DefaultImageLimits.MaxDecodeBytes {
+		return fmt.Errorf("%w: %d frames of %dx%d need about %d MB, the limit is %d MB",
+			ErrImageTooLarge, len(a.frames), b.Dx(), b.Dy(), total>>20, DefaultImageLimits.MaxDecodeBytes>>20)
+	}
+
+	for i, frame := range a.frames {
+		img := first
+		if i > 0 {
+			if img, err = edit(frame); err != nil {
+				return err
+			}
+		}
+
+		out.Image = append(out.Image, quantizeForGIF(img))
+		out.Delay = append(out.Delay, a.delays[i])
+		// every frame is complete, so nothing needs restoring between them
+		out.Disposal = append(out.Disposal, gif.DisposalBackground)
+	}
+
+	return gif.EncodeAll(w, out)
+}
+
+// quantizeForGIF reduces img to a 256 colour palette. GIF has only on/off
+// transparency, so partly transparent pixels are snapped first.
+func quantizeForGIF(img image.Image) *image.Paletted {
+	src := cloneNRGBA(toNRGBA(img))
+	for i := 3; i < len(src.Pix); i += 4 {
+		if src.Pix[i] < 128 {
+			src.Pix[i-3], src.Pix[i-2], src.Pix[i-1], src.Pix[i] = 0, 0, 0, 0
+		} else {
+			src.Pix[i] = 255
+		}
+	}
+
+	paletted := QuantizeImage(src, 256)
+
+	// the GIF encoder only honours fully transparent palette entries
+	for i, c := range paletted.Palette {
+		if nrgba, ok := c.(color.NRGBA); ok && nrgba.A < 128 {
+			paletted.Palette[i] = color.NRGBA{}
+		}
+	}
+
+	return paletted
+}
+
+func cloneNRGBA(img *image.NRGBA) *image.NRGBA {
+	clone := image.NewNRGBA(img.Bounds())
+	copy(clone.Pix, img.Pix)
+	return clone
+}
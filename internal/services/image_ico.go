@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// MaxICOSize is the largest width/height an icon entry can describe
+const MaxICOSize = 256
+
+type icoEntry struct {
+	width, height int
+	offset, size  int
+}
+
+func icoEntries(data []byte) ([]icoEntry, error) {
+	if len(data) < 6 || binary.LittleEndian.Uint16(data[0:2]) != 0 || binary.LittleEndian.Uint16(data[2:4]) != 1 {
+		return nil, fmt.Errorf("invalid ICO header")
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	if count == 0 || 6+count*16 > len(data) {
+		return nil, fmt.Errorf("invalid ICO directory")
+	}
+
+	entries := make([]icoEntry, 0, count)
+	for i := 0; i < count; i++ {
+		dir := data[6+i*16:]
+
+		// 0 means 256 in the one-byte size fields
+		entry := icoEntry{
+			width:  int(dir[0]),
+			height: int(dir[1]),
+			size:   int(binary.LittleEndian.Uint32(dir[8:12])),
+			offset: int(binary.LittleEndian.Uint32(dir[12:16])),
+		}
+		if entry.width == 0 {
+			entry.width = 256
+		}
+		if entry.height == 0 {
+			entry.height = 256
+		}
+		if entry.offset < 0 || entry.size <= 0 || entry.offset+entry.size > len(data) {
+			return nil, fmt.Errorf("ICO entry %d is out of range", i+1)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// decodeICO returns the largest image in the icon
+func decodeICO(data []byte) (image.Image, error) {
+	entries, err := icoEntries(data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := largestICOEntry(data, entries)
+	if bytes.HasPrefix(payload, pngSignature) {
+		return png.Decode(bytes.NewReader(payload))
+	}
+
+	return decodeDIB(payload)
+}
+
+// largestICOEntry returns the payload of the biggest entry according to the
+// icon directory
+func largestICOEntry(data []byte, entries []icoEntry) []byte {
+	best := entries[0]
+	for _, entry := range entries[1:] {
+		if entry.width*entry.height > best.width*best.height {
+			best = entry
+		}
+	}
+	return data[best.offset : best.offset+best.size]
+}
+
+// decodeICOConfig reports the size of the entry decodeICO would return. PNG
+// entries are measured from their own header since the directory can lie.
+func decodeICOConfig(data []byte) (image.Config, error) {
+	entries, err := icoEntries(data)
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	payload := largestICOEntry(data, entries)
+	if bytes.HasPrefix(payload, pngSignature) {
+		return png.DecodeConfig(bytes.NewReader(payload))
+	}
+
+	if len(payload) < 16 {
+		return image.Config{}, fmt.Errorf("ICO bitmap too short")
+	}
+	return image.Config{
+		ColorModel: color.NRGBAModel,
+		Width:      int(int32(binary.LittleEndian.Uint32(payload[4:8]))),
+		Height:     int(int32(binary.LittleEndian.Uint32(payload[8:12]))) / 2,
+	}, nil
+}
+
+// decodeDIB reads the headerless bitmap used inside ICO files: a
+// BITMAPINFOHEADER whose height covers both the colour bitmap and the 1-bit
+// AND transparency mask that follows it
+func decodeDIB(data []byte) (image.Image, error) {
+	if len(data) < 40 {
+		return nil, fmt.Errorf("ICO bitmap too short")
+	}
+
+	headerSize := int(binary.LittleEndian.Uint32(data[0:4]))
+	width := int(int32(binary.LittleEndian.Uint32(data[4:8])))
+	height := int(int32(binary.LittleEndian.Uint32(data[8:12]))) / 2
+	bpp := int(binary.LittleEndian.Uint16(data[14:16]))
+	compression := binary.LittleEndian.Uint32(data[16:20])
+	colorsUsed := int(binary.LittleEndian.Uint32(data[32:36]))
+
+	if width <= 0 || height <= 0 || width > MaxICOSize || height > MaxICOSize || headerSize < 40 {
+		return nil, fmt.Errorf("invalid ICO bitmap dimensions")
+	}
+	if compression != 0 {
+		return nil, fmt.Errorf("compressed ICO bitmaps are not supported")
+	}
+
+	var palette []color.NRGBA
+	pos := headerSize
+	if bpp <= 8 {
+		if colorsUsed == 0 {
+			colorsUsed = 1 << bpp
+		}
+		if pos+colorsUsed*4 > len(data) {
+			return nil, fmt.Errorf("ICO palette out of range")
+		}
+		for i := 0; i < colorsUsed; i++ {
+			p := data[pos+i*4:]
+			palette = append(palette, color.NRGBA{R: p[2], G: p[1], B: p[0], A: 255})
+		}
+		pos += colorsUsed * 4
+	}
+
+	switch bpp {
+	case 1, 4, 8, 24, 32:
+	default:
+		return nil, fmt.Errorf("unsupported ICO bit depth: %d", bpp)
+	}
+
+	// rows are padded to 4 bytes and stored bottom-up
+	stride := ((width*bpp + 31) / 32) * 4
+	maskStride := ((width + 31) / 32) * 4
+	maskStart := pos + stride*height
+	if maskStart > len(data) {
+		return nil, fmt.Errorf("ICO bitmap out of range")
+	}
+	hasMask := maskStart+maskStride*height <= len(data)
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := data[pos+(height-1-y)*stride:]
+		for x := 0; x < width; x++ {
+			var c color.NRGBA
+
+			switch bpp {
+			case 32:
+				c = color.NRGBA{R: row[x*4+2], G: row[x*4+1], B: row[x*4], A: row[x*4+3]}
+			case 24:
+				c = color.NRGBA{R: row[x*3+2], G: row[x*3+1], B: row[x*3], A: 255}
+			default:
+				bit := x * bpp
+				index := int(row[bit/8]>>(8-bpp-bit%8)) & (1<<bpp - 1)
+				if index < len(palette) {
+					c = palette[index]
+				}
+			}
+
+			// bitmaps without an alpha channel use the AND mask
+			if bpp != 32 && hasMask {
+				mask := data[maskStart+(height-1-y)*maskStride:]
+				if mask[x/8]&(0x80>>(x%8)) != 0 {
+					c.A = 0
+				}
+			}
+
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	return img, nil
+}
+
+// EncodeICO writes an icon holding one PNG entry per image. PNG entries are
+// understood by every browser and by Windows since Vista.
+func EncodeICO(w io.Writer, images []image.Image) error {
+	if len(images) == 0 {
+		return fmt.Errorf("an icon needs at least one image")
+	}
+
+	payloads := make([][]byte, len(images))
+	for i, img := range images {
+		b := img.Bounds()
+		if b.Dx() > MaxICOSize || b.Dy() > MaxICOSize {
+			return fmt.Errorf("icon images cannot be larger than %dx%d", MaxICOSize, MaxICOSize)
+		}
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		payloads[i] = buf.Bytes()
+	}
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, [3]uint16{0, 1, uint16(len(images))})
+
+	offset := 6 + 16*len(images)
+	for i, img := range images {
+		b := img.Bounds()
+		binary.Write(&out, binary.LittleEndian, struct {
+			Width, Height, Colors, Reserved uint8
+			Planes, BitCount                uint16
+			Size, Offset                    uint32
+		}{
+			Width:    uint8(b.Dx() % 256),
+			Height:   uint8(b.Dy() % 256),
+			Planes:   1,
+			BitCount: 32,
+			Size:     uint32(len(payloads[i])),
+			Offset:   uint32(offset),
+		})
+		offset += len(payloads[i])
+	}
+
+	for _, payload := range payloads {
+		out.Write(payload)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrImageTooLarge is returned when an upload would decode to more pixels,
+// frames or memory than the limits allow. Compressed formats can declare
+// enormous dimensions in a few bytes, so this is checked from the header
+// before any pixels are decoded.
+var ErrImageTooLarge = errors.New("image is too large to process")
+
+type ImageLimits struct {
+	// MaxMegapixels caps width × height of a single frame
+	MaxMegapixels float64
+
+	// MaxFrames caps the frames of each multi-frame format, keyed by format
+	// name. GIF and WebP animations are decoded frame by frame so their
+	// limits also bound memory; TIFF and AVIF only have their first image
+	// decoded, so theirs bound the files accepted. Formats without an entry
+	// are single frame.
+	MaxFrames map[string]int
+
+	// MaxDecodeBytes caps the estimated memory needed to hold the decoded
+	// image, counting every frame of an animation
+	MaxDecodeBytes int64
+}
+
+// DefaultImageLimits applies to every decode of a user supplied image. The
+// defaults can be changed with IMAGE_MAX_MEGAPIXELS, IMAGE_MAX_FRAMES (every
+// format), IMAGE_MAX_FRAMES_GIF, IMAGE_MAX_FRAMES_WEBP, IMAGE_MAX_FRAMES_AVIF,
+// IMAGE_MAX_FRAMES_TIFF and IMAGE_DECODE_BUDGET_MB.
+var DefaultImageLimits = imageLimitsFromEnv()
+
+func imageLimitsFromEnv() ImageLimits {
+	limits := ImageLimits{
+		MaxMegapixels: 50,
+		MaxFrames: map[string]int{
+			"gif":  300,
+			"webp": 300,
+			"avif": 300,
+			"tiff": 100,
+		},
+		MaxDecodeBytes: 512 << 20,
+	}
+
+	if v, err := strconv.ParseFloat(os.Getenv("IMAGE_MAX_MEGAPIXELS"), 64); err == nil && v > 0 {
+		limits.MaxMegapixels = v
+	}
+	allFrames, _ := strconv.Atoi(os.Getenv("IMAGE_MAX_FRAMES"))
+	for format := range limits.MaxFrames {
+		if allFrames > 0 {
+			limits.MaxFrames[format] = allFrames
+		}
+		if v, err := strconv.Atoi(os.Getenv("IMAGE_MAX_FRAMES_" + strings.ToUpper(format))); err == nil && v > 0 {
+			limits.MaxFrames[format] = v
+		}
+	}
+	if v, err := strconv.ParseInt(os.Getenv("IMAGE_DECODE_BUDGET_MB"), 10, 64); err == nil && v > 0 {
+		limits.MaxDecodeBytes = v << 20
+	}
+
+	return limits
+}
+
+// checkImageLimits inspects the header of an image already identified by
+// DetectImageFormat and rejects it if decoding would exceed the limits
+func checkImageLimits(data []byte, format string) error {
+	limits := DefaultImageLimits
+
+	// avifdec runs out of process and writes the first frame; decodeAVIF
+	// checks the PNG it produces
+	if format == "avif" {
+		return checkFrameLimit(countAVIFFrames(data), format, limits)
+	}
+
+	config, err := decodeImageConfig(data, format)
+	if err != nil {
+		return fmt.Errorf("failed to read image header: %w", err)
+	}
+
+	pixels := int64(config.Width) * int64(config.Height)
+	if float64(pixels) > limits.MaxMegapixels*1e6 {
+		return fmt.Errorf("%w: %dx%d is %.1f megapixels, the limit is %g",
+			ErrImageTooLarge, config.Width, config.Height, float64(pixels)/1e6, limits.MaxMegapixels)
+	}
+
+	// frames counts the images in the file, decoded the ones held in memory
+	frames, decoded := 1, 1
+	switch format {
+	case "gif":
+		frames, err = countGIFFrames(data)
+		if err != nil {
+			return err
+		}
+		decoded = frames
+	case "webp":
+		frames = max(1, countWebPFrames(data))
+		decoded = frames
+	case "tiff":
+		frames = countTIFFPages(data)
+	}
+	if err := checkFrameLimit(frames, format, limits); err != nil {
+		return err
+	}
+
+	// decoded pixels are held as 4 bytes (8 for 16-bit formats), and
+	// animations keep a composited copy of every frame plus a canvas
+	bytesPerPixel := int64(4)
+	if is16BitModel(config.ColorModel) {
+		bytesPerPixel = 8
+	}
+	budget := pixels * bytesPerPixel * int64(decoded+1)
+	if budget > limits.MaxDecodeBytes {
+		return fmt.Errorf("%w: decoding needs about %d MB, the limit is %d MB",
+			ErrImageTooLarge, budget>>20, limits.MaxDecodeBytes>>20)
+	}
+
+	return nil
+}
+
+func checkFrameLimit(frames int, format string, limits ImageLimits) error {
+	limit, ok := limits.MaxFrames[format]
+	if !ok || frames <= limit {
+		return nil
+	}
+	return fmt.Errorf("%w: %d frames, the limit for %s is %d", ErrImageTooLarge, frames, format, limit)
+}
+
+func is16BitModel(model color.Model) bool {
+	return model == color.RGBA64Model || model == color.NRGBA64Model || model == color.Gray16Model
+}
+
+// countGIFFrames walks the GIF block structure without decompressing any
+// image data
+func countGIFFrames(data []byte) (int, error) {
+	malformed := fmt.Errorf("malformed GIF")
+
+	if len(data) < 13 {
+		return 0, malformed
+	}
+
+	pos := 13
+	// global colour table
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << ((flags & 0x07) + 1)
+	}
+
+	skipSubBlocks := func() bool {
+		for pos < len(data) {
+			size := int(data[pos])
+			pos++
+			if size == 0 {
+				return true
+			}
+			pos += size
+		}
+		return false
+	}
+
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // extension
+			pos += 2
+			if !skipSubBlocks() {
+				return frames, nil
+			}
+
+		case 0x2C: // image descriptor
+			if pos+10 > len(data) {
+				return frames, nil
+			}
+			frames++
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << ((flags & 0x07) + 1)
+			}
+			pos++ // LZW minimum code size
+			if !skipSubBlocks() {
+				return frames, nil
+			}
+
+		case 0x3B: // trailer
+			return frames, nil
+
+		default:
+			if frames == 0 {
+				return 0, malformed
+			}
+			return frames, nil
+		}
+	}
+
+	return frames, nil
+}
+
+// countWebPFrames counts ANMF chunks; still images have none
+func countWebPFrames(data []byte) int {
+	frames := 0
+
+	pos := 12
+	for pos+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		if string(data[pos:pos+4]) == "ANMF" {
+			frames++
+		}
+		if size < 0 || pos+8+size > len(data) {
+			break
+		}
+		pos += 8 + size + size%2
+	}
+
+	return frames
+}
+
+// countTIFFPages follows the chain of image file directories, stopping at
+// the first offset that loops back or runs past the data
+func countTIFFPages(data []byte) int {
+	if len(data) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[0] == 'M' {
+		order = binary.BigEndian
+	}
+
+	pages := 0
+	seen := make(map[uint32]bool)
+	offset := order.Uint32(data[4:8])
+	for offset != 0 && !seen[offset] && int64(offset)+2 <= int64(len(data)) {
+		seen[offset] = true
+		pages++
+
+		entries := int64(order.Uint16(data[offset:]))
+		next := int64(offset) + 2 + entries*12
+		if next+4 > int64(len(data)) {
+			break
+		}
+		offset = order.Uint32(data[next:])
+	}
+
+	return max(1, pages)
+}
+
+// countAVIFFrames reads the sample count of the image sequence tracks in an
+// animated AVIF; still images have no moov box and count as one frame
+func countAVIFFrames(data []byte) int {
+	frames := 1
+
+	var walk func(b []byte)
+	walk = func(b []byte) {
+		walkISOBoxes(b, func(boxType string, body []byte) {
+			switch boxType {
+			case "moov", "trak", "mdia", "minf", "stbl":
+				walk(body)
+			case "stsz":
+				// version and flags, sample size, sample count
+				if len(body) >= 12 {
+					frames = max(frames, int(binary.BigEndian.Uint32(body[8:12])))
+				}
+			}
+		})
+	}
+	walk(data)
+
+	return frames
+}
+
+// walkISOBoxes calls fn with the type and body of each ISO BMFF box in data
+func walkISOBoxes(data []byte, fn func(boxType string, body []byte)) {
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[0:4]))
+		header := uint64(8)
+		switch size {
+		case 0: // runs to the end of the data
+			size = uint64(len(data))
+		case 1: // 64-bit size follows the type
+			if len(data) < 16 {
+				return
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return
+		}
+
+		fn(string(data[4:8]), data[header:size])
+		data = data[size:]
+	}
+}
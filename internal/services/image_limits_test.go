@@ -0,0 +1,244 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func encodeTestGIF(t *testing.T, frames int) []byte {
+	t.Helper()
+
+	palette := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{}
+	for range frames {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 4, 4), palette))
+		anim.Delay = append(anim.Delay, 10)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatalf("encode GIF: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCountGIFFrames(t *testing.T) {
+	three := encodeTestGIF(t, 3)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    int
+		wantErr bool
+	}{
+		{name: "single frame", data: encodeTestGIF(t, 1), want: 1},
+		{name: "animation", data: three, want: 3},
+		{name: "missing trailer", data: three[:len(three)-1], want: 3},
+		{name: "truncated frame data", data: three[:len(three)-8], want: 3},
+		{name: "header only", data: three[:13], want: 0},
+		{name: "too short", data: []byte("GIF89a"), wantErr: true},
+		{name: "garbage after header", data: append(append([]byte{}, three[:13]...), 0x00, 0x01), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := countGIFFrames(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %d frames", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("countGIFFrames: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d frames, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func riffChunk(fourCC string, body []byte) []byte {
+	chunk := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+	chunk = append(chunk, body...)
+	if len(body)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func webpFile(chunks ...[]byte) []byte {
+	var body []byte
+	for _, c := range chunks {
+		body = append(body, c...)
+	}
+	out := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(4+len(body)))...)
+	out = append(out, "WEBP"...)
+	return append(out, body...)
+}
+
+func TestCountWebPFrames(t *testing.T) {
+	anmf := riffChunk("ANMF", make([]byte, 17))
+	truncated := webpFile(riffChunk("ANIM", make([]byte, 6)), anmf)
+	truncated = append(truncated, "ANMF"...)
+	truncated = binary.LittleEndian.AppendUint32(truncated, 1000)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"still image", webpFile(riffChunk("VP8L", make([]byte, 5))), 0},
+		{"animation", webpFile(riffChunk("VP8X", make([]byte, 10)), riffChunk("ANIM", make([]byte, 6)), anmf, anmf, anmf), 3},
+		{"truncated last frame", truncated, 2},
+		{"header only", webpFile(), 0},
+		{"too short", []byte("RIFF"), 0},
+	}
+
+	for _, tt := range tests {
+		if got := countWebPFrames(tt.data); got != tt.want {
+			t.Errorf("%s: got %d frames, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// tiffFile lays out IFDs with no entries one after another; next holds the
+// offset each IFD points to, 0 ending the chain
+func tiffFile(order binary.AppendByteOrder, next ...uint32) []byte {
+	out := []byte("II*\x00")
+	if order == binary.AppendByteOrder(binary.BigEndian) {
+		out = []byte("MM\x00*")
+	}
+	out = order.AppendUint32(out, 8)
+	for _, n := range next {
+		out = order.AppendUint16(out, 0)
+		out = order.AppendUint32(out, n)
+	}
+	return out
+}
+
+func TestCountTIFFPages(t *testing.T) {
+	// each IFD with no entries takes 6 bytes, the first starting at 8
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"single page", tiffFile(binary.LittleEndian, 0), 1},
+		{"three pages", tiffFile(binary.LittleEndian, 14, 20, 0), 3},
+		{"big endian", tiffFile(binary.BigEndian, 14, 0), 2},
+		{"loop back to the first IFD", tiffFile(binary.LittleEndian, 14, 8), 2},
+		{"self loop", tiffFile(binary.LittleEndian, 8), 1},
+		{"offset past the end", tiffFile(binary.LittleEndian, 14, 1<<30), 2},
+		{"entries past the end", append(tiffFile(binary.LittleEndian, 14), 0xFF, 0xFF), 2},
+		{"first IFD past the end", []byte("II*\x00\xFF\xFF\x00\x00"), 1},
+		{"too short", []byte("II*\x00"), 1},
+	}
+
+	for _, tt := range tests {
+		if got := countTIFFPages(tt.data); got != tt.want {
+			t.Errorf("%s: got %d pages, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func isoBox(boxType string, body ...[]byte) []byte {
+	var content []byte
+	for _, b := range body {
+		content = append(content, b...)
+	}
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(content)))
+	out = append(out, boxType...)
+	return append(out, content...)
+}
+
+func stszBox(samples uint32) []byte {
+	body := make([]byte, 8) // version and flags, sample size
+	return isoBox("stsz", binary.BigEndian.AppendUint32(body, samples))
+}
+
+func avifTrack(samples uint32) []byte {
+	return isoBox("trak", isoBox("mdia", isoBox("minf", isoBox("stbl", stszBox(samples)))))
+}
+
+func TestCountAVIFFrames(t *testing.T) {
+	ftyp := isoBox("ftyp", []byte("avis"))
+	meta := isoBox("meta", make([]byte, 12))
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"still image", concat(ftyp, meta), 1},
+		{"image sequence", concat(ftyp, isoBox("moov", avifTrack(48))), 48},
+		{"largest track wins", concat(ftyp, isoBox("moov", avifTrack(12), avifTrack(30))), 30},
+		{"empty sequence", concat(ftyp, isoBox("moov", avifTrack(0))), 1},
+		{"truncated stsz", concat(ftyp, isoBox("moov", isoBox("trak", isoBox("mdia", isoBox("minf", isoBox("stbl", isoBox("stsz", make([]byte, 10)))))))), 1},
+		{"empty", nil, 1},
+	}
+
+	for _, tt := range tests {
+		if got := countAVIFFrames(tt.data); got != tt.want {
+			t.Errorf("%s: got %d frames, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWalkISOBoxes(t *testing.T) {
+	large := binary.BigEndian.AppendUint32(nil, 1)
+	large = append(large, "mdat"...)
+	large = binary.BigEndian.AppendUint64(large, 16+3)
+	large = append(large, "abc"...)
+
+	toEnd := binary.BigEndian.AppendUint32(nil, 0)
+	toEnd = append(toEnd, "mdat"...)
+	toEnd = append(toEnd, "rest"...)
+
+	oversized := binary.BigEndian.AppendUint32(nil, 100)
+	oversized = append(oversized, "free"...)
+
+	undersized := binary.BigEndian.AppendUint32(nil, 4)
+	undersized = append(undersized, "free"...)
+
+	tests := []struct {
+		name string
+		data []byte
+		want []string // type:body of each box visited
+	}{
+		{"plain boxes", concat(isoBox("ftyp", []byte("avif")), isoBox("free")), []string{"ftyp:avif", "free:"}},
+		{"64-bit size", concat(large, isoBox("free")), []string{"mdat:abc", "free:"}},
+		{"size zero runs to the end", concat(isoBox("ftyp"), toEnd), []string{"ftyp:", "mdat:rest"}},
+		{"size past the end stops", concat(isoBox("ftyp"), oversized), []string{"ftyp:"}},
+		{"size smaller than the header stops", concat(undersized, isoBox("free")), nil},
+		{"truncated 64-bit header stops", large[:12], nil},
+		{"trailing bytes are ignored", concat(isoBox("ftyp"), []byte{0, 0, 0}), []string{"ftyp:"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			walkISOBoxes(tt.data, func(boxType string, body []byte) {
+				got = append(got, boxType+":"+string(body))
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("visited %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("visited %q, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
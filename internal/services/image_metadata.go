@@ -0,0 +1,732 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxXMPLength keeps oversized XMP packets (some editors embed thumbnails)
+// out of the JSON response
+const maxXMPLength = 64 << 10
+
+type GPSLocation struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Altitude  *float64 `json:"altitude,omitempty"`
+}
+
+// ImageMetadata is the embedded information we can read from an image.
+// The headline fields are pulled out of EXIF; EXIF holds every tag we know
+// the name of, formatted for display.
+type ImageMetadata struct {
+	Format      string            `json:"format"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Camera      string            `json:"camera,omitempty"`
+	Lens        string            `json:"lens,omitempty"`
+	Software    string            `json:"software,omitempty"`
+	TakenAt     string            `json:"taken_at,omitempty"`
+	Orientation int               `json:"orientation,omitempty"`
+	GPS         *GPSLocation      `json:"gps,omitempty"`
+	EXIF        map[string]string `json:"exif,omitempty"`
+	XMP         string            `json:"xmp,omitempty"`
+	HasICC      bool              `json:"has_icc"`
+	ICCProfile  string            `json:"icc_profile,omitempty"`
+}
+
+// imageSegments holds the raw metadata blocks found in a file
+type imageSegments struct {
+	format string
+	exif   []byte // TIFF structure, without the JPEG "Exif\0\0" prefix
+	xmp    []byte
+	icc    []byte
+}
+
+// ReadImageMetadata reports the EXIF, XMP and ICC information in the image
+func ReadImageMetadata(input io.Reader) (*ImageMetadata, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	format := DetectImageFormat(data)
+	if format == "" {
+		return nil, fmt.Errorf("unsupported input format")
+	}
+
+	config, err := decodeImageConfig(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	meta := &ImageMetadata{
+		Format: format,
+		Width:  config.Width,
+		Height: config.Height,
+	}
+
+	segments := extractImageSegments(data)
+
+	if len(segments.exif) > 0 {
+		// a broken EXIF block should not stop us showing everything else
+		if tags, err := parseEXIF(segments.exif); err == nil {
+			meta.applyEXIF(tags)
+		}
+	}
+
+	if len(segments.xmp) > 0 {
+		xmp := strings.TrimSpace(string(segments.xmp))
+		if len(xmp) > maxXMPLength {
+			xmp = xmp[:maxXMPLength]
+		}
+		meta.XMP = xmp
+	}
+
+	if len(segments.icc) > 0 {
+		meta.HasICC = true
+		meta.ICCProfile = iccDescription(segments.icc)
+	}
+
+	return meta, nil
+}
+
+func (m *ImageMetadata) applyEXIF(tags exifTags) {
+	m.EXIF = make(map[string]string)
+	for tag, entry := range tags.ifd0 {
+		if name, ok := exifTagNames[tag]; ok {
+			m.EXIF[name] = entry.display(tag)
+		}
+	}
+	for tag, entry := range tags.exif {
+		if name, ok := exifTagNames[tag]; ok {
+			m.EXIF[name] = entry.display(tag)
+		}
+	}
+
+	m.Camera = strings.TrimSpace(tags.ifd0.str(0x010F) + " " + tags.ifd0.str(0x0110))
+	// many cameras repeat the make inside the model, e.g. "Canon Canon EOS R5"
+	if cameraMake := tags.ifd0.str(0x010F); cameraMake != "" && strings.HasPrefix(tags.ifd0.str(0x0110), cameraMake) {
+		m.Camera = tags.ifd0.str(0x0110)
+	}
+	m.Lens = tags.exif.str(0xA434)
+	m.Software = tags.ifd0.str(0x0131)
+
+	m.TakenAt = tags.exif.str(0x9003)
+	if m.TakenAt == "" {
+		m.TakenAt = tags.ifd0.str(0x0132)
+	}
+	if offset := tags.exif.str(0x9011); offset != "" && m.TakenAt != "" {
+		m.TakenAt += " " + offset
+	}
+
+	m.Orientation = tags.orientation()
+	m.GPS = tags.gpsLocation()
+}
+
+// extractImageSegments pulls the metadata blocks out of JPEG, PNG and WebP
+// files without decoding any pixels
+func extractImageSegments(data []byte) imageSegments {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return jpegSegments(data)
+	case bytes.HasPrefix(data, pngSignature):
+		return pngSegments(data)
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return webpSegments(data)
+	}
+	return imageSegments{}
+}
+
+var (
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+	jpegEXIFPrefix = []byte("Exif\x00\x00")
+	jpegXMPPrefix  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	jpegICCPrefix  = []byte("ICC_PROFILE\x00")
+)
+
+// jpegSegment is one marker segment before the image data
+type jpegSegment struct {
+	marker byte
+	start  int // offset of the 0xFF byte
+	end    int // offset just past the segment
+	data   []byte
+}
+
+// jpegHeaderSegments lists the segments up to (not including) the start of
+// scan and returns the offset where the scan begins
+func jpegHeaderSegments(data []byte) ([]jpegSegment, int, error) {
+	var segments []jpegSegment
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, 0, fmt.Errorf("invalid JPEG marker at offset %d", pos)
+		}
+
+		marker := data[pos+1]
+		if marker == 0xFF {
+			// fill byte
+			pos++
+			continue
+		}
+		if marker == 0xDA {
+			return segments, pos, nil
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			segments = append(segments, jpegSegment{marker: marker, start: pos, end: pos + 2})
+			pos += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, 0, fmt.Errorf("truncated JPEG segment at offset %d", pos)
+		}
+
+		segments = append(segments, jpegSegment{
+			marker: marker,
+			start:  pos,
+			end:    end,
+			data:   data[pos+4 : end],
+		})
+		pos = end
+	}
+
+	return nil, 0, fmt.Errorf("JPEG has no image data")
+}
+
+func jpegSegments(data []byte) imageSegments {
+	result := imageSegments{format: "jpeg"}
+
+	segments, _, err := jpegHeaderSegments(data)
+	if err != nil {
+		return result
+	}
+
+	// ICC profiles over 64KB are split across numbered APP2 segments
+	iccChunks := make(map[byte][]byte)
+	for _, seg := range segments {
+		switch {
+		case seg.marker == 0xE1 && bytes.HasPrefix(seg.data, jpegEXIFPrefix) && result.exif == nil:
+			result.exif = seg.data[len(jpegEXIFPrefix):]
+		case seg.marker == 0xE1 && bytes.HasPrefix(seg.data, jpegXMPPrefix) && result.xmp == nil:
+			result.xmp = seg.data[len(jpegXMPPrefix):]
+		case seg.marker == 0xE2 && bytes.HasPrefix(seg.data, jpegICCPrefix) && len(seg.data) > len(jpegICCPrefix)+2:
+			iccChunks[seg.data[len(jpegICCPrefix)]] = seg.data[len(jpegICCPrefix)+2:]
+		}
+	}
+	for i := byte(1); int(i) <= len(iccChunks); i++ {
+		result.icc = append(result.icc, iccChunks[i]...)
+	}
+
+	return result
+}
+
+// pngChunk is one chunk of a PNG file; raw covers length, type, data and CRC
+type pngChunk struct {
+	typ  string
+	data []byte
+	raw  []byte
+}
+
+func pngChunks(data []byte) ([]pngChunk, error) {
+	var chunks []pngChunk
+
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("truncated PNG chunk at offset %d", pos)
+		}
+
+		chunk := pngChunk{
+			typ:  string(data[pos+4 : pos+8]),
+			data: data[pos+8 : pos+8+length],
+			raw:  data[pos:end],
+		}
+		chunks = append(chunks, chunk)
+		pos = end
+
+		if chunk.typ == "IEND" {
+			return chunks, nil
+		}
+	}
+
+	return nil, fmt.Errorf("PNG has no IEND chunk")
+}
+
+func pngSegments(data []byte) imageSegments {
+	result := imageSegments{format: "png"}
+
+	chunks, err := pngChunks(data)
+	if err != nil {
+		return result
+	}
+
+	for _, chunk := range chunks {
+		switch chunk.typ {
+		case "eXIf":
+			result.exif = chunk.data
+
+		case "iTXt":
+			// keyword \0 compression-flag method language \0 translated \0 text
+			keyword, rest, ok := bytes.Cut(chunk.data, []byte{0})
+			if !ok || string(keyword) != "XML:com.adobe.xmp" || len(rest) < 2 || rest[0] != 0 {
+				continue
+			}
+			parts := bytes.SplitN(rest[2:], []byte{0}, 3)
+			if len(parts) == 3 {
+				result.xmp = parts[2]
+			}
+
+		case "iCCP":
+			// name \0 method compressed-profile
+			_, rest, ok := bytes.Cut(chunk.data, []byte{0})
+			if !ok || len(rest) < 1 {
+				continue
+			}
+			if profile, err := inflateLimited(rest[1:], 4<<20); err == nil {
+				result.icc = profile
+			}
+		}
+	}
+
+	return result
+}
+
+// webpChunk is one RIFF chunk; raw includes the header and padding
+type webpChunk struct {
+	fourCC string
+	data   []byte
+	raw    []byte
+}
+
+func webpChunks(data []byte) ([]webpChunk, error) {
+	var chunks []webpChunk
+
+	pos := 12
+	for pos+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		end := pos + 8 + size
+		if size < 0 || end > len(data) {
+			return nil, fmt.Errorf("truncated WebP chunk at offset %d", pos)
+		}
+
+		rawEnd := end + size%2
+		if rawEnd > len(data) {
+			rawEnd = end
+		}
+
+		chunks = append(chunks, webpChunk{
+			fourCC: string(data[pos : pos+4]),
+			data:   data[pos+8 : end],
+			raw:    data[pos:rawEnd],
+		})
+		pos = rawEnd
+	}
+
+	return chunks, nil
+}
+
+func webpSegments(data []byte) imageSegments {
+	result := imageSegments{format: "webp"}
+
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return result
+	}
+
+	for _, chunk := range chunks {
+		switch chunk.fourCC {
+		case "EXIF":
+			// some writers keep the JPEG style prefix
+			result.exif = bytes.TrimPrefix(chunk.data, jpegEXIFPrefix)
+		case "XMP ":
+			result.xmp = chunk.data
+		case "ICCP":
+			result.icc = chunk.data
+		}
+	}
+
+	return result
+}
+
+func inflateLimited(data []byte, limit int64) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return io.ReadAll(io.LimitReader(zr, limit))
+}
+
+// iccDescription returns the profile's human readable name, e.g. "Display P3"
+func iccDescription(profile []byte) string {
+	if len(profile) < 132 {
+		return ""
+	}
+
+	count := int(binary.BigEndian.Uint32(profile[128:132]))
+	for i := 0; i < count && 132+(i+1)*12 <= len(profile); i++ {
+		entry := profile[132+i*12:]
+		if string(entry[0:4]) != "desc" {
+			continue
+		}
+
+		offset := int(binary.BigEndian.Uint32(entry[4:8]))
+		size := int(binary.BigEndian.Uint32(entry[8:12]))
+		if offset < 0 || size < 12 || offset+size > len(profile) {
+			return ""
+		}
+		tag := profile[offset : offset+size]
+
+		switch string(tag[0:4]) {
+		case "desc":
+			// ICC v2: ASCII count then the string
+			n := int(binary.BigEndian.Uint32(tag[8:12]))
+			if n > 0 && 12+n <= len(tag) {
+				return strings.TrimRight(string(tag[12:12+n]), "\x00 ")
+			}
+
+		case "mluc":
+			// ICC v4: localized UTF-16BE records, take the first one
+			if len(tag) < 28 {
+				return ""
+			}
+			length := int(binary.BigEndian.Uint32(tag[20:24]))
+			start := int(binary.BigEndian.Uint32(tag[24:28]))
+			if start < 0 || length < 0 || start+length > len(tag) {
+				return ""
+			}
+			units := make([]uint16, length/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(tag[start+j*2:])
+			}
+			return strings.TrimRight(string(utf16.Decode(units)), "\x00 ")
+		}
+
+		return ""
+	}
+
+	return ""
+}
+
+// exifEntry is one IFD entry with its value bytes resolved
+type exifEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+	order binary.ByteOrder
+}
+
+type exifIFD map[uint16]exifEntry
+
+type exifTags struct {
+	ifd0 exifIFD
+	exif exifIFD
+	gps  exifIFD
+}
+
+var exifTypeSizes = map[uint16]int{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8,
+}
+
+// parseEXIF reads IFD0 and the Exif and GPS sub-IFDs of a TIFF structure
+func parseEXIF(tiff []byte) (exifTags, error) {
+	var tags exifTags
+
+	if len(tiff) < 8 {
+		return tags, fmt.Errorf("EXIF block too short")
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return tags, fmt.Errorf("invalid EXIF byte order")
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return tags, fmt.Errorf("invalid EXIF header")
+	}
+
+	var err error
+	tags.ifd0, err = readIFD(tiff, order, order.Uint32(tiff[4:8]))
+	if err != nil {
+		return tags, err
+	}
+
+	if ptr, ok := tags.ifd0.uint(0x8769); ok {
+		tags.exif, _ = readIFD(tiff, order, uint32(ptr))
+	}
+	if ptr, ok := tags.ifd0.uint(0x8825); ok {
+		tags.gps, _ = readIFD(tiff, order, uint32(ptr))
+	}
+
+	return tags, nil
+}
+
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) (exifIFD, error) {
+	if int(offset)+2 > len(tiff) {
+		return nil, fmt.Errorf("IFD offset out of range")
+	}
+
+	count := int(order.Uint16(tiff[offset:]))
+	if count > 1000 || int(offset)+2+count*12 > len(tiff) {
+		return nil, fmt.Errorf("IFD entries out of range")
+	}
+
+	ifd := make(exifIFD, count)
+	for i := 0; i < count; i++ {
+		raw := tiff[int(offset)+2+i*12:]
+
+		entry := exifEntry{
+			typ:   order.Uint16(raw[2:4]),
+			count: order.Uint32(raw[4:8]),
+			order: order,
+		}
+
+		size, ok := exifTypeSizes[entry.typ]
+		if !ok {
+			continue
+		}
+		total := uint64(size) * uint64(entry.count)
+
+		if total <= 4 {
+			entry.value = raw[8 : 8+total]
+		} else {
+			start := uint64(order.Uint32(raw[8:12]))
+			if start+total > uint64(len(tiff)) {
+				continue
+			}
+			entry.value = tiff[start : start+total]
+		}
+
+		ifd[order.Uint16(raw[0:2])] = entry
+	}
+
+	return ifd, nil
+}
+
+func (e exifEntry) ints() []int64 {
+	var values []int64
+	switch e.typ {
+	case 1, 7:
+		for _, b := range e.value {
+			values = append(values, int64(b))
+		}
+	case 3:
+		for i := 0; i+2 <= len(e.value); i += 2 {
+			values = append(values, int64(e.order.Uint16(e.value[i:])))
+		}
+	case 4:
+		for i := 0; i+4 <= len(e.value); i += 4 {
+			values = append(values, int64(e.order.Uint32(e.value[i:])))
+		}
+	case 9:
+		for i := 0; i+4 <= len(e.value); i += 4 {
+			values = append(values, int64(int32(e.order.Uint32(e.value[i:]))))
+		}
+	}
+	return values
+}
+
+// rationals returns numerator/denominator pairs
+func (e exifEntry) rationals() [][2]int64 {
+	var values [][2]int64
+	for i := 0; i+8 <= len(e.value); i += 8 {
+		num, den := int64(e.order.Uint32(e.value[i:])), int64(e.order.Uint32(e.value[i+4:]))
+		if e.typ == 10 {
+			num, den = int64(int32(num)), int64(int32(den))
+		}
+		values = append(values, [2]int64{num, den})
+	}
+	return values
+}
+
+func (e exifEntry) floats() []float64 {
+	var values []float64
+	for _, r := range e.rationals() {
+		if r[1] == 0 {
+			values = append(values, 0)
+			continue
+		}
+		values = append(values, float64(r[0])/float64(r[1]))
+	}
+	return values
+}
+
+// display formats the value for people, with units for the common tags
+func (e exifEntry) display(tag uint16) string {
+	switch e.typ {
+	case 2:
+		return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+
+	case 5, 10:
+		rationals := e.rationals()
+		if len(rationals) == 0 {
+			return ""
+		}
+		r := rationals[0]
+		value := e.floats()[0]
+
+		switch tag {
+		case 0x829A:
+			if r[0] > 0 && value < 1 {
+				return fmt.Sprintf("1/%d s", int64(math.Round(float64(r[1])/float64(r[0]))))
+			}
+			return fmt.Sprintf("%g s", value)
+		case 0x829D:
+			return fmt.Sprintf("f/%.1f", value)
+		case 0x920A:
+			return fmt.Sprintf("%g mm", math.Round(value*10)/10)
+		}
+
+		parts := make([]string, 0, len(rationals))
+		for _, f := range e.floats() {
+			parts = append(parts, fmt.Sprintf("%g", math.Round(f*1000)/1000))
+		}
+		return strings.Join(parts, ", ")
+
+	case 1, 3, 4, 9:
+		ints := e.ints()
+		parts := make([]string, 0, len(ints))
+		for _, v := range ints {
+			parts = append(parts, fmt.Sprintf("%d", v))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	// UNDEFINED values are mostly binary, only show short printable ones
+	if len(e.value) <= 32 && isPrintable(e.value) {
+		return strings.TrimRight(string(e.value), "\x00")
+	}
+	return fmt.Sprintf("(%d bytes)", len(e.value))
+}
+
+func (ifd exifIFD) str(tag uint16) string {
+	entry, ok := ifd[tag]
+	if !ok || entry.typ != 2 {
+		return ""
+	}
+	return entry.display(tag)
+}
+
+func (ifd exifIFD) uint(tag uint16) (int64, bool) {
+	entry, ok := ifd[tag]
+	if !ok {
+		return 0, false
+	}
+	ints := entry.ints()
+	if len(ints) == 0 {
+		return 0, false
+	}
+	return ints[0], true
+}
+
+// orientation returns the EXIF Orientation (1-8), or 0 when unset
+func (t exifTags) orientation() int {
+	v, ok := t.ifd0.uint(0x0112)
+	if !ok || v < 1 || v > 8 {
+		return 0
+	}
+	return int(v)
+}
+
+func (t exifTags) gpsLocation() *GPSLocation {
+	lat, latOK := gpsCoordinate(t.gps, 0x0002, 0x0001, "S")
+	lon, lonOK := gpsCoordinate(t.gps, 0x0004, 0x0003, "W")
+	if !latOK || !lonOK {
+		return nil
+	}
+
+	location := &GPSLocation{Latitude: lat, Longitude: lon}
+
+	if alt, ok := t.gps[0x0006]; ok && len(alt.floats()) > 0 {
+		altitude := alt.floats()[0]
+		// AltitudeRef 1 means below sea level
+		if ref, ok := t.gps.uint(0x0005); ok && ref == 1 {
+			altitude = -altitude
+		}
+		location.Altitude = &altitude
+	}
+
+	return location
+}
+
+// gpsCoordinate converts degrees/minutes/seconds to signed decimal degrees
+func gpsCoordinate(gps exifIFD, valueTag, refTag uint16, negativeRef string) (float64, bool) {
+	entry, ok := gps[valueTag]
+	if !ok {
+		return 0, false
+	}
+	dms := entry.floats()
+	if len(dms) != 3 {
+		return 0, false
+	}
+
+	value := dms[0] + dms[1]/60 + dms[2]/3600
+	if strings.EqualFold(gps.str(refTag), negativeRef) {
+		value = -value
+	}
+
+	return math.Round(value*1e6) / 1e6, true
+}
+
+func isPrintable(data []byte) bool {
+	for _, b := range bytes.TrimRight(data, "\x00") {
+		if b < 0x20 || b > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+var exifTagNames = map[uint16]string{
+	0x010E: "ImageDescription",
+	0x010F: "Make",
+	0x0110: "Model",
+	0x0112: "Orientation",
+	0x011A: "XResolution",
+	0x011B: "YResolution",
+	0x0131: "Software",
+	0x0132: "DateTime",
+	0x013B: "Artist",
+	0x8298: "Copyright",
+	0x829A: "ExposureTime",
+	0x829D: "FNumber",
+	0x8822: "ExposureProgram",
+	0x8827: "ISO",
+	0x9000: "ExifVersion",
+	0x9003: "DateTimeOriginal",
+	0x9004: "DateTimeDigitized",
+	0x9010: "OffsetTime",
+	0x9011: "OffsetTimeOriginal",
+	0x9201: "ShutterSpeedValue",
+	0x9202: "ApertureValue",
+	0x9204: "ExposureBiasValue",
+	0x9207: "MeteringMode",
+	0x9209: "Flash",
+	0x920A: "FocalLength",
+	0xA001: "ColorSpace",
+	0xA002: "PixelXDimension",
+	0xA003: "PixelYDimension",
+	0xA405: "FocalLengthIn35mmFilm",
+	0xA406: "SceneCaptureType",
+	0xA430: "CameraOwnerName",
+	0xA431: "BodySerialNumber",
+	0xA433: "LensMake",
+	0xA434: "LensModel",
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+// quantizeSampleLimit bounds how many pixels feed the palette search; larger
+// images are sampled on a regular grid
+const quantizeSampleLimit = 250_000
+
+// QuantizeImage reduces img to at most colors entries using median cut and
+// Floyd-Steinberg dithering. Alpha is quantized like any other channel so
+// transparent PNGs keep their transparency.
+func QuantizeImage(img image.Image, colors int) *image.Paletted {
+	if colors < 2 || colors > 256 {
+		colors = 256
+	}
+
+	palette := medianCutPalette(samplePixels(img, quantizeSampleLimit), colors)
+
+	b := img.Bounds()
+	dst := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette)
+	draw.FloydSteinberg.Draw(dst, dst.Bounds(), img, b.Min)
+	return dst
+}
+
+// samplePixels returns up to limit colours taken evenly across img
+func samplePixels(img image.Image, limit int) []color.NRGBA {
+	b := img.Bounds()
+
+	step := 1
+	for (b.Dx()/step)*(b.Dy()/step) > limit {
+		step++
+	}
+
+	pixels := make([]color.NRGBA, 0, (b.Dx()/step+1)*(b.Dy()/step+1))
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			pixels = append(pixels, color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA))
+		}
+	}
+
+	return pixels
+}
+
+// colorBox is a group of pixels in median cut; boxes are split along their
+// widest channel until there are enough of them
+type colorBox struct {
+	pixels []color.NRGBA
+
+	// channel with the largest spread, and that spread
+	channel int
+	spread  int
+}
+
+func newColorBox(pixels []color.NRGBA) colorBox {
+	box := colorBox{pixels: pixels}
+	box.channel, box.spread = box.widest()
+	return box
+}
+
+func (b colorBox) value(p color.NRGBA, c int) uint8 {
+	switch c {
+	case 0:
+		return p.R
+	case 1:
+		return p.G
+	case 2:
+		return p.B
+	default:
+		return p.A
+	}
+}
+
+// widest returns the channel with the largest spread and that spread
+func (b colorBox) widest() (int, int) {
+	bestChannel, bestRange := 0, -1
+	for c := 0; c < 4; c++ {
+		lo, hi := 255, 0
+		for _, p := range b.pixels {
+			v := int(b.value(p, c))
+			lo = min(lo, v)
+			hi = max(hi, v)
+		}
+		if hi-lo > bestRange {
+			bestChannel, bestRange = c, hi-lo
+		}
+	}
+	return bestChannel, bestRange
+}
+
+func (b colorBox) average() color.NRGBA {
+	var r, g, bl, a int
+	for _, p := range b.pixels {
+		r += int(p.R)
+		g += int(p.G)
+		bl += int(p.B)
+		a += int(p.A)
+	}
+	n := len(b.pixels)
+	return color.NRGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: uint8(a / n)}
+}
+
+// medianCutGroups splits pixels into at most n groups of similar colours,
+// largest groups first
+func medianCutGroups(pixels []color.NRGBA, n int) []colorBox {
+	if len(pixels) == 0 {
+		return nil
+	}
+
+	boxes := []colorBox{newColorBox(pixels)}
+	for len(boxes) < n {
+		// split the box with the widest channel spread, weighted by size
+		// so large flat areas still get their share of colours
+		best, bestScore := -1, 0
+		for i, box := range boxes {
+			if len(box.pixels) < 2 || box.spread == 0 {
+				continue
+			}
+			if score := box.spread * len(box.pixels); score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		sort.Slice(box.pixels, func(i, j int) bool {
+			return box.value(box.pixels[i], box.channel) < box.value(box.pixels[j], box.channel)
+		})
+
+		mid := len(box.pixels) / 2
+		boxes[best] = newColorBox(box.pixels[:mid])
+		boxes = append(boxes, newColorBox(box.pixels[mid:]))
+	}
+
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return len(boxes[i].pixels) > len(boxes[j].pixels)
+	})
+
+	return boxes
+}
+
+func medianCutPalette(pixels []color.NRGBA, n int) color.Palette {
+	boxes := medianCutGroups(pixels, n)
+
+	palette := make(color.Palette, 0, len(boxes))
+	for _, box := range boxes {
+		palette = append(palette, box.average())
+	}
+	if len(palette) == 0 {
+		palette = append(palette, color.NRGBA{})
+	}
+
+	return palette
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestQuantizeImage(t *testing.T) {
+	gradient := image.NewNRGBA(image.Rect(10, 20, 74, 84))
+	for y := 20; y < 84; y++ {
+		for x := 10; x < 74; x++ {
+			gradient.Set(x, y, color.NRGBA{uint8(x * 3), uint8(y * 2), uint8(x + y), 255})
+		}
+	}
+
+	tests := []struct {
+		name       string
+		colors     int
+		maxPalette int
+	}{
+		{"16 colours", 16, 16},
+		{"2 colours", 2, 2},
+		{"too few falls back to 256", 1, 256},
+		{"too many falls back to 256", 1000, 256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := QuantizeImage(gradient, tt.colors)
+			if len(dst.Palette) == 0 || len(dst.Palette) > tt.maxPalette {
+				t.Errorf("palette has %d colours, want 1-%d", len(dst.Palette), tt.maxPalette)
+			}
+			if got, want := dst.Bounds(), image.Rect(0, 0, 64, 64); got != want {
+				t.Errorf("bounds = %v, want %v", got, want)
+			}
+			for _, idx := range dst.Pix {
+				if int(idx) >= len(dst.Palette) {
+					t.Fatalf("pixel uses index %d of a %d colour palette", idx, len(dst.Palette))
+				}
+			}
+		})
+	}
+}
+
+func TestQuantizeImageKeepsFewColours(t *testing.T) {
+	colours := []color.NRGBA{
+		{255, 0, 0, 255},
+		{0, 0, 255, 255},
+		{0, 0, 0, 0},
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, 30, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 30; x++ {
+			img.SetNRGBA(x, y, colours[x/10])
+		}
+	}
+
+	dst := QuantizeImage(img, 8)
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 30; x++ {
+			got := color.NRGBAModel.Convert(dst.At(x, y)).(color.NRGBA)
+			want := colours[x/10]
+			if want.A == 0 {
+				if got.A != 0 {
+					t.Fatalf("pixel (%d,%d) = %v, want transparent", x, y, got)
+				}
+				continue
+			}
+			if got != want {
+				t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestQuantizeImageEmpty(t *testing.T) {
+	dst := QuantizeImage(image.NewNRGBA(image.Rect(0, 0, 0, 0)), 16)
+	if !dst.Bounds().Empty() {
+		t.Errorf("bounds = %v, want empty", dst.Bounds())
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"io"
+	"strings"
+)
+
+// pngKeepChunks are the ancillary PNG chunks that affect how pixels are
+// displayed; every other ancillary chunk (text, time, EXIF, ICC) is dropped
+var pngKeepChunks = map[string]bool{
+	"IHDR": true, "PLTE": true, "IDAT": true, "IEND": true,
+	"tRNS": true, "gAMA": true, "cHRM": true, "sRGB": true, "sBIT": true,
+	"bKGD": true, "pHYs": true,
+	// animated PNG
+	"acTL": true, "fcTL": true, "fdAT": true,
+}
+
+// VP8X feature flags for the metadata chunks
+const (
+	webpFlagICC  = 0x20
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// StripImageMetadata removes EXIF, XMP, ICC, comments and text chunks from
+// the image and returns its format. In "lossless" mode (the default) JPEG,
+// PNG and WebP files are rewritten without the metadata segments so the
+// pixels are untouched; "reencode" decodes and encodes the image again.
+// The Orientation tag is the one thing kept by lossless mode, since dropping
+// it would turn photos sideways.
+func StripImageMetadata(input io.Reader, output io.Writer, mode string) (string, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	segments := extractImageSegments(data)
+
+	switch strings.ToLower(mode) {
+	case "", "lossless":
+		var stripped []byte
+		orientation := 0
+		if len(segments.exif) > 0 {
+			if tags, err := parseEXIF(segments.exif); err == nil {
+				orientation = tags.orientation()
+			}
+		}
+
+		switch segments.format {
+		case "jpeg":
+			stripped, err = stripJPEG(data, orientation)
+		case "png":
+			stripped, err = stripPNG(data, orientation)
+		case "webp":
+			stripped, err = stripWebP(data, orientation)
+		default:
+			return "", fmt.Errorf("lossless stripping supports JPEG, PNG and WebP only")
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := output.Write(stripped); err != nil {
+			return "", err
+		}
+		return segments.format, nil
+
+	case "reencode":
+		// SVG has no pixels to re-encode, and encodeImage would flatten an
+		// animation to its first frame
+		switch format := DetectImageFormat(data); {
+		case isSVG(data):
+			return "", fmt.Errorf("SVG files cannot be re-encoded; use the SVG tools to clean them")
+		case format == "gif":
+			anim, err := decodeGIFAnimation(data)
+			if err != nil {
+				return "", err
+			}
+			keep := func(img image.Image) (image.Image, error) { return img, nil }
+			if err := anim.encode(output, keep); err != nil {
+				return "", fmt.Errorf("failed to encode image: %w", err)
+			}
+			return "gif", nil
+		case format == "webp" && countWebPFrames(data) > 1:
+			return "", fmt.Errorf("animated WebP files cannot be re-encoded; use lossless mode")
+		}
+
+		// decodeImage applies the orientation, and our encoders never
+		// write metadata
+		img, format, err := decodeImage(bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+		if err := encodeImage(output, img, format, 92); err != nil {
+			return "", fmt.Errorf("failed to encode image: %w", err)
+		}
+		return format, nil
+
+	default:
+		return "", fmt.Errorf("unknown strip mode: %q", mode)
+	}
+}
+
+// orientationEXIF builds a TIFF structure holding only the Orientation tag
+func orientationEXIF(orientation int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("MM\x00\x2a")
+	binary.Write(&buf, binary.BigEndian, uint32(8)) // IFD0 offset
+	binary.Write(&buf, binary.BigEndian, uint16(1)) // entry count
+	binary.Write(&buf, binary.BigEndian, uint16(0x0112))
+	binary.Write(&buf, binary.BigEndian, uint16(3)) // SHORT
+	binary.Write(&buf, binary.BigEndian, uint32(1))
+	binary.Write(&buf, binary.BigEndian, uint16(orientation))
+	binary.Write(&buf, binary.BigEndian, uint16(0))
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // no next IFD
+	return buf.Bytes()
+}
+
+func stripJPEG(data []byte, orientation int) ([]byte, error) {
+	segments, scanStart, err := jpegHeaderSegments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:2])
+
+	// the replacement EXIF goes right after JFIF, or first if there is none
+	if len(segments) > 0 && segments[0].marker == 0xE0 {
+		out.Write(data[segments[0].start:segments[0].end])
+		segments = segments[1:]
+	}
+	if orientation > 1 {
+		payload := append(append([]byte{}, jpegEXIFPrefix...), orientationEXIF(orientation)...)
+		out.Write([]byte{0xFF, 0xE1})
+		binary.Write(&out, binary.BigEndian, uint16(len(payload)+2))
+		out.Write(payload)
+	}
+
+	for _, seg := range segments {
+		keep := true
+		switch {
+		case seg.marker == 0xFE:
+			// comment
+			keep = false
+		case seg.marker == 0xEE:
+			// APP14 carries Adobe's colour transform flag, which decoders need
+			keep = bytes.HasPrefix(seg.data, []byte("Adobe"))
+		case seg.marker >= 0xE1 && seg.marker <= 0xEF:
+			keep = false
+		}
+
+		if keep {
+			out.Write(data[seg.start:seg.end])
+		}
+	}
+
+	out.Write(data[scanStart:])
+	return out.Bytes(), nil
+}
+
+func stripPNG(data []byte, orientation int) ([]byte, error) {
+	chunks, err := pngChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(pngSignature)
+
+	for _, chunk := range chunks {
+		if chunk.typ == "IDAT" && orientation > 1 {
+			// eXIf must come before the image data
+			writePNGChunk(&out, "eXIf", orientationEXIF(orientation))
+			orientation = 0
+		}
+		if pngKeepChunks[chunk.typ] {
+			out.Write(chunk.raw)
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+func writePNGChunk(w *bytes.Buffer, typ string, data []byte) {
+	binary.Write(w, binary.BigEndian, uint32(len(data)))
+	w.WriteString(typ)
+	w.Write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	binary.Write(w, binary.BigEndian, crc.Sum32())
+}
+
+func stripWebP(data []byte, orientation int) ([]byte, error) {
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	for _, chunk := range chunks {
+		switch chunk.fourCC {
+		case "EXIF", "XMP ", "ICCP":
+			continue
+		case "VP8X":
+			// clear the metadata flags, keeping alpha and animation
+			vp8x := append([]byte{}, chunk.raw...)
+			if len(vp8x) > 8 {
+				vp8x[8] &^= webpFlagICC | webpFlagEXIF | webpFlagXMP
+				if orientation > 1 {
+					vp8x[8] |= webpFlagEXIF
+				}
+			}
+			body.Write(vp8x)
+		default:
+			body.Write(chunk.raw)
+		}
+	}
+
+	// simple (VP8/VP8L only) files have no VP8X header to flag EXIF in,
+	// so the orientation can only be kept on extended files
+	hasVP8X := len(chunks) > 0 && chunks[0].fourCC == "VP8X"
+	if orientation > 1 && hasVP8X {
+		exif := orientationEXIF(orientation)
+		body.WriteString("EXIF")
+		binary.Write(&body, binary.LittleEndian, uint32(len(exif)))
+		body.Write(exif)
+		if len(exif)%2 == 1 {
+			body.WriteByte(0)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("RIFF")
+	binary.Write(&out, binary.LittleEndian, uint32(body.Len()+4))
+	out.WriteString("WEBP")
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
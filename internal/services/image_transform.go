@@ -0,0 +1,306 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// MaxImageDimension caps the width and height produced by any operation
+const MaxImageDimension = 10000
+
+// ErrInvalidImageOperation is returned when an edit chain or watermark asks
+// for something that cannot be done, such as an unknown operation or a crop
+// outside the image
+var ErrInvalidImageOperation = errors.New("invalid image operation")
+
+// MaxImageOperations caps the length of an edit chain; every step allocates
+// a new image
+const MaxImageOperations = 20
+
+// ImageOperation is one step of an edit chain. Which fields are used
+// depends on Op:
+//
+//	resize: Mode (exact, fit, fill, percent), Width, Height, Percent
+//	crop:   X, Y, Width, Height
+//	rotate: Angle (90, 180 or 270, clockwise)
+//	flip:   Direction (horizontal or vertical)
+type ImageOperation struct {
+	Op        string  `json:"op"`
+	Mode      string  `json:"mode,omitempty"`
+	X         int     `json:"x,omitempty"`
+	Y         int     `json:"y,omitempty"`
+	Width     int     `json:"width,omitempty"`
+	Height    int     `json:"height,omitempty"`
+	Percent   float64 `json:"percent,omitempty"`
+	Angle     int     `json:"angle,omitempty"`
+	Direction string  `json:"direction,omitempty"`
+}
+
+// ApplyImageOperations runs the chain in order and returns the result
+func ApplyImageOperations(img image.Image, ops []ImageOperation) (image.Image, error) {
+	if len(ops) > MaxImageOperations {
+		return nil, fmt.Errorf("%w: at most %d operations can be applied at once", ErrInvalidImageOperation, MaxImageOperations)
+	}
+
+	for i, op := range ops {
+		var err error
+
+		switch strings.ToLower(op.Op) {
+		case "resize":
+			img, err = resizeImage(img, op)
+		case "crop":
+			img, err = cropImage(img, op)
+		case "rotate":
+			img, err = rotateImage(img, op.Angle)
+		case "flip":
+			img, err = flipImage(img, op.Direction)
+		default:
+			err = fmt.Errorf("%w: unknown operation: %q", ErrInvalidImageOperation, op.Op)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i+1, err)
+		}
+	}
+
+	return img, nil
+}
+
+func resizeImage(img image.Image, op ImageOperation) (image.Image, error) {
+	src := img.Bounds()
+	srcW, srcH := float64(src.Dx()), float64(src.Dy())
+
+	var w, h int
+
+	switch strings.ToLower(op.Mode) {
+	case "percent":
+		if op.Percent <= 0 {
+			return nil, fmt.Errorf("%w: resize percentage must be positive", ErrInvalidImageOperation)
+		}
+		w = int(math.Round(srcW * op.Percent / 100))
+		h = int(math.Round(srcH * op.Percent / 100))
+
+	case "exact", "":
+		if op.Width <= 0 && op.Height <= 0 {
+			return nil, fmt.Errorf("%w: resize needs a width or a height", ErrInvalidImageOperation)
+		}
+		// a missing side keeps the aspect ratio
+		w, h = op.Width, op.Height
+		if w <= 0 {
+			w = int(math.Round(srcW * float64(h) / srcH))
+		}
+		if h <= 0 {
+			h = int(math.Round(srcH * float64(w) / srcW))
+		}
+
+	case "fit":
+		if op.Width <= 0 || op.Height <= 0 {
+			return nil, fmt.Errorf("%w: fit needs both a width and a height", ErrInvalidImageOperation)
+		}
+		scale := math.Min(float64(op.Width)/srcW, float64(op.Height)/srcH)
+		w = int(math.Round(srcW * scale))
+		h = int(math.Round(srcH * scale))
+
+	case "fill":
+		if op.Width <= 0 || op.Height <= 0 {
+			return nil, fmt.Errorf("%w: fill needs both a width and a height", ErrInvalidImageOperation)
+		}
+		return fillImage(img, op.Width, op.Height)
+
+	default:
+		return nil, fmt.Errorf("%w: unknown resize mode: %q", ErrInvalidImageOperation, op.Mode)
+	}
+
+	return scaleImage(img, max(w, 1), max(h, 1))
+}
+
+// fillImage scales img to cover w×h and crops the overflow around the centre
+func fillImage(img image.Image, w, h int) (image.Image, error) {
+	src := img.Bounds()
+	scale := math.Max(float64(w)/float64(src.Dx()), float64(h)/float64(src.Dy()))
+
+	// the source area that ends up inside the target once scaled
+	cropW := min(src.Dx(), int(math.Round(float64(w)/scale)))
+	cropH := min(src.Dy(), int(math.Round(float64(h)/scale)))
+	x := src.Min.X + (src.Dx()-cropW)/2
+	y := src.Min.Y + (src.Dy()-cropH)/2
+
+	if err := checkImageDimensions(w, h); err != nil {
+		return nil, err
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, image.Rect(x, y, x+cropW, y+cropH), draw.Src, nil)
+	return dst, nil
+}
+
+// scaleImage resamples with Catmull-Rom, which stays sharp when shrinking
+// photos without the ringing of Lanczos
+func scaleImage(img image.Image, w, h int) (image.Image, error) {
+	if err := checkImageDimensions(w, h); err != nil {
+		return nil, err
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return dst, nil
+}
+
+func cropImage(img image.Image, op ImageOperation) (image.Image, error) {
+	if op.Width <= 0 || op.Height <= 0 {
+		return nil, fmt.Errorf("%w: crop needs a positive width and height", ErrInvalidImageOperation)
+	}
+
+	src := img.Bounds()
+	rect := image.Rect(op.X, op.Y, op.X+op.Width, op.Y+op.Height).Add(src.Min)
+	if !rect.In(src) {
+		return nil, fmt.Errorf("%w: crop rectangle %dx%d+%d+%d is outside the %dx%d image", ErrInvalidImageOperation,
+			op.Width, op.Height, op.X, op.Y, src.Dx(), src.Dy())
+	}
+
+	if err := checkImageDimensions(rect.Dx(), rect.Dy()); err != nil {
+		return nil, err
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst, nil
+}
+
+func rotateImage(img image.Image, angle int) (image.Image, error) {
+	angle = ((angle % 360) + 360) % 360
+	if angle%90 != 0 {
+		return nil, fmt.Errorf("%w: rotation must be a multiple of 90 degrees", ErrInvalidImageOperation)
+	}
+	if angle == 0 {
+		return img, nil
+	}
+
+	b := img.Bounds()
+	if err := checkImageDimensions(b.Dx(), b.Dy()); err != nil {
+		return nil, err
+	}
+
+	src := toNRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	dstW, dstH := w, h
+	if angle != 180 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch angle {
+			case 90:
+				dx, dy = h-1-y, x
+			case 180:
+				dx, dy = w-1-x, h-1-y
+			case 270:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+
+	return dst, nil
+}
+
+func flipImage(img image.Image, direction string) (image.Image, error) {
+	b := img.Bounds()
+	if err := checkImageDimensions(b.Dx(), b.Dy()); err != nil {
+		return nil, err
+	}
+
+	src := toNRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	switch strings.ToLower(direction) {
+	case "horizontal", "h":
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				copy(dst.Pix[dst.PixOffset(w-1-x, y):dst.PixOffset(w-1-x, y)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+			}
+		}
+
+	case "vertical", "v":
+		for y := 0; y < h; y++ {
+			copy(dst.Pix[dst.PixOffset(0, h-1-y):dst.PixOffset(0, h-1-y)+w*4], src.Pix[src.PixOffset(0, y):src.PixOffset(0, y)+w*4])
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: flip direction must be horizontal or vertical", ErrInvalidImageOperation)
+	}
+
+	return dst, nil
+}
+
+// toNRGBA returns img as a zero-origin NRGBA so pixels can be moved around
+// directly
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Bounds().Min == (image.Point{}) {
+		return nrgba
+	}
+
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// checkImageDimensions is called before allocating any new image. Besides
+// the per-side limit it applies DefaultImageLimits, so an edit chain cannot
+// upscale a small upload past what decoding would have allowed.
+func checkImageDimensions(w, h int) error {
+	if w > MaxImageDimension || h > MaxImageDimension {
+		return fmt.Errorf("%w: the resulting %dx%d image exceeds the %dpx limit", ErrImageTooLarge, w, h, MaxImageDimension)
+	}
+
+	limits := DefaultImageLimits
+	pixels := int64(w) * int64(h)
+	if float64(pixels) > limits.MaxMegapixels*1e6 {
+		return fmt.Errorf("%w: the resulting %dx%d image is %.1f megapixels, the limit is %g",
+			ErrImageTooLarge, w, h, float64(pixels)/1e6, limits.MaxMegapixels)
+	}
+	// NRGBA holds 4 bytes per pixel
+	if pixels*4 > limits.MaxDecodeBytes {
+		return fmt.Errorf("%w: the resulting %dx%d image needs about %d MB, the limit is %d MB",
+			ErrImageTooLarge, w, h, pixels*4>>20, limits.MaxDecodeBytes>>20)
+	}
+	return nil
+}
+
+// applyOrientation turns an image stored with an EXIF Orientation tag
+// upright. Values 2, 4, 5 and 7 are mirrored as well as rotated.
+func applyOrientation(img image.Image, orientation int) (image.Image, error) {
+	var ops []ImageOperation
+
+	switch orientation {
+	case 2:
+		ops = []ImageOperation{{Op: "flip", Direction: "horizontal"}}
+	case 3:
+		ops = []ImageOperation{{Op: "rotate", Angle: 180}}
+	case 4:
+		ops = []ImageOperation{{Op: "flip", Direction: "vertical"}}
+	case 5:
+		ops = []ImageOperation{{Op: "rotate", Angle: 90}, {Op: "flip", Direction: "horizontal"}}
+	case 6:
+		ops = []ImageOperation{{Op: "rotate", Angle: 90}}
+	case 7:
+		ops = []ImageOperation{{Op: "rotate", Angle: 270}, {Op: "flip", Direction: "horizontal"}}
+	case 8:
+		ops = []ImageOperation{{Op: "rotate", Angle: 270}}
+	default:
+		return img, nil
+	}
+
+	return ApplyImageOperations(img, ops)
+}
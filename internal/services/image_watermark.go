@@ -0,0 +1,316 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
+)
+
+// MaxWatermarkTextLength keeps stamps to a line of text
+const MaxWatermarkTextLength = 200
+
+// Watermark describes a text or logo stamp composited over an image after
+// any edit operations. Sizes that are 0 scale with the image, so the same
+// watermark suits every output size.
+type Watermark struct {
+	// Text is drawn in the embedded Go Bold font unless a logo is given
+	Text string `json:"text,omitempty"`
+
+	// FontSize in pixels; 0 means 1/20 of the image's shorter side
+	FontSize float64 `json:"font_size,omitempty"`
+
+	// Color of the text as #rrggbb, white by default
+	Color string `json:"color,omitempty"`
+
+	// Logo is an encoded image (any decodable format or SVG) used instead
+	// of text. It is uploaded separately, so it has no JSON field.
+	Logo []byte `json:"-"`
+
+	// Scale is the logo width as a fraction of the image width, 0.2 by
+	// default
+	Scale float64 `json:"scale,omitempty"`
+
+	// Opacity from 0 to 1, 0.5 by default
+	Opacity float64 `json:"opacity,omitempty"`
+
+	// Position: top-left, top, top-right, left, center, right, bottom-left,
+	// bottom or bottom-right (the default). Ignored when tiling.
+	Position string `json:"position,omitempty"`
+
+	// Margin from the image edge in pixels; 0 means 3% of the shorter side
+	Margin int `json:"margin,omitempty"`
+
+	// Angle rotates the stamp counterclockwise, in degrees
+	Angle float64 `json:"angle,omitempty"`
+
+	// Tile repeats the stamp across the whole image
+	Tile bool `json:"tile,omitempty"`
+}
+
+var watermarkFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(gobold.TTF)
+})
+
+// watermarker holds a validated watermark with its logo decoded once, so
+// it can be applied to many frames or pages
+type watermarker struct {
+	wm    Watermark
+	color color.NRGBA
+	logo  image.Image
+	svg   bool
+
+	// stamps are cached per target size, since animation frames and
+	// document pages usually share one
+	mu     sync.Mutex
+	stamps map[image.Point]*image.NRGBA
+}
+
+func newWatermarker(wm *Watermark) (*watermarker, error) {
+	if wm == nil {
+		return nil, nil
+	}
+
+	w := &watermarker{wm: *wm, stamps: make(map[image.Point]*image.NRGBA)}
+
+	if w.wm.Opacity <= 0 || w.wm.Opacity > 1 {
+		w.wm.Opacity = 0.5
+	}
+	if w.wm.Scale <= 0 || w.wm.Scale > 1 {
+		w.wm.Scale = 0.2
+	}
+	if w.wm.FontSize < 0 || w.wm.FontSize > 1000 {
+		return nil, fmt.Errorf("%w: watermark font size must be between 1 and 1000 pixels", ErrInvalidImageOperation)
+	}
+	if w.wm.Margin < 0 {
+		return nil, fmt.Errorf("%w: watermark margin cannot be negative", ErrInvalidImageOperation)
+	}
+	if _, _, err := watermarkAnchor(w.wm.Position); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(w.wm.Logo) > 0:
+		if isSVG(w.wm.Logo) {
+			w.svg = true
+		} else {
+			logo, _, err := decodeImage(bytes.NewReader(w.wm.Logo))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode watermark logo: %w", err)
+			}
+			w.logo = logo
+		}
+
+	case strings.TrimSpace(w.wm.Text) != "":
+		if len(w.wm.Text) > MaxWatermarkTextLength {
+			return nil, fmt.Errorf("%w: watermark text is limited to %d characters", ErrInvalidImageOperation, MaxWatermarkTextLength)
+		}
+		c, err := parseHexColor(w.wm.Color, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+		if err != nil {
+			return nil, err
+		}
+		w.color = c
+
+	default:
+		return nil, fmt.Errorf("%w: a watermark needs text or a logo", ErrInvalidImageOperation)
+	}
+
+	return w, nil
+}
+
+func (w *watermarker) apply(img image.Image) (image.Image, error) {
+	b := img.Bounds()
+	size := image.Pt(b.Dx(), b.Dy())
+
+	w.mu.Lock()
+	stamp, ok := w.stamps[size]
+	w.mu.Unlock()
+	if !ok {
+		var err error
+		if stamp, err = w.render(size); err != nil {
+			return nil, err
+		}
+		w.mu.Lock()
+		w.stamps[size] = stamp
+		w.mu.Unlock()
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size.X, size.Y))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+
+	mask := image.NewUniform(color.Alpha{A: uint8(math.Round(w.wm.Opacity * 255))})
+	sw, sh := stamp.Bounds().Dx(), stamp.Bounds().Dy()
+
+	if w.wm.Tile {
+		// a brick pattern, with every other row shifted by half a step
+		stepX, stepY := sw+max(sw/2, 1), sh+max(sh*2, 1)
+		for row, y := 0, -sh/2; y < size.Y; row, y = row+1, y+stepY {
+			offset := 0
+			if row%2 == 1 {
+				offset = stepX / 2
+			}
+			for x := -offset; x < size.X; x += stepX {
+				r := image.Rect(x, y, x+sw, y+sh)
+				draw.DrawMask(dst, r, stamp, image.Point{}, mask, image.Point{}, draw.Over)
+			}
+		}
+		return dst, nil
+	}
+
+	margin := w.wm.Margin
+	if margin == 0 {
+		margin = min(size.X, size.Y) * 3 / 100
+	}
+
+	ax, ay, _ := watermarkAnchor(w.wm.Position)
+	x := margin + int(ax*float64(size.X-sw-2*margin))
+	y := margin + int(ay*float64(size.Y-sh-2*margin))
+
+	r := image.Rect(x, y, x+sw, y+sh)
+	draw.DrawMask(dst, r, stamp, image.Point{}, mask, image.Point{}, draw.Over)
+	return dst, nil
+}
+
+// render draws the stamp at full opacity for an image of the given size
+func (w *watermarker) render(size image.Point) (*image.NRGBA, error) {
+	var stamp *image.NRGBA
+
+	if w.logo != nil || w.svg {
+		width := max(1, int(math.Round(float64(size.X)*w.wm.Scale)))
+
+		if w.svg {
+			logo, err := RasterizeSVG(w.wm.Logo, width, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render watermark logo: %w", err)
+			}
+			stamp = logo
+		} else {
+			lb := w.logo.Bounds()
+			height := max(1, int(math.Round(float64(lb.Dy())*float64(width)/float64(lb.Dx()))))
+			logo, err := scaleImage(w.logo, width, height)
+			if err != nil {
+				return nil, err
+			}
+			stamp = toNRGBA(logo)
+		}
+	} else {
+		fontSize := w.wm.FontSize
+		if fontSize == 0 {
+			fontSize = math.Max(8, float64(min(size.X, size.Y))/20)
+		}
+
+		text, err := renderWatermarkText(w.wm.Text, fontSize, w.color)
+		if err != nil {
+			return nil, err
+		}
+		stamp = text
+	}
+
+	if w.wm.Angle != 0 {
+		stamp = rotateStamp(stamp, w.wm.Angle)
+	}
+	return stamp, nil
+}
+
+func renderWatermarkText(text string, size float64, c color.NRGBA) (*image.NRGBA, error) {
+	f, err := watermarkFont()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watermark font: %w", err)
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watermark font: %w", err)
+	}
+	defer face.Close()
+
+	metrics := face.Metrics()
+	width := font.MeasureString(face, text).Ceil()
+	height := (metrics.Ascent + metrics.Descent).Ceil()
+	if err := checkImageDimensions(width, height); err != nil {
+		return nil, err
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.Point26_6{Y: metrics.Ascent},
+	}
+	d.DrawString(text)
+
+	return img, nil
+}
+
+// rotateStamp rotates src counterclockwise by angle degrees about its centre
+// onto a canvas just large enough to hold it
+func rotateStamp(src *image.NRGBA, angle float64) *image.NRGBA {
+	rad := angle * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+
+	sw, sh := float64(src.Bounds().Dx()), float64(src.Bounds().Dy())
+	dw := math.Ceil(math.Abs(sw*cos) + math.Abs(sh*sin))
+	dh := math.Ceil(math.Abs(sw*sin) + math.Abs(sh*cos))
+
+	dst := image.NewNRGBA(image.Rect(0, 0, int(dw), int(dh)))
+
+	// y points down, so a counterclockwise turn uses the transposed matrix
+	m := f64.Aff3{
+		cos, sin, dw/2 - (cos*sw/2 + sin*sh/2),
+		-sin, cos, dh/2 - (-sin*sw/2 + cos*sh/2),
+	}
+	draw.BiLinear.Transform(dst, m, src, src.Bounds(), draw.Over, nil)
+
+	return dst
+}
+
+// watermarkAnchor maps a position name to fractions of the free space
+func watermarkAnchor(position string) (float64, float64, error) {
+	switch strings.ToLower(position) {
+	case "top-left":
+		return 0, 0, nil
+	case "top":
+		return 0.5, 0, nil
+	case "top-right":
+		return 1, 0, nil
+	case "left":
+		return 0, 0.5, nil
+	case "center", "centre":
+		return 0.5, 0.5, nil
+	case "right":
+		return 1, 0.5, nil
+	case "bottom-left":
+		return 0, 1, nil
+	case "bottom":
+		return 0.5, 1, nil
+	case "bottom-right", "":
+		return 1, 1, nil
+	}
+	return 0, 0, fmt.Errorf("%w: unknown watermark position: %q", ErrInvalidImageOperation, position)
+}
+
+// parseHexColor reads #rrggbb, returning fallback for an empty string
+func parseHexColor(s string, fallback color.NRGBA) (color.NRGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if s == "" {
+		return fallback, nil
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		return color.NRGBA{}, fmt.Errorf("%w: invalid colour: %q", ErrInvalidImageOperation, s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"strings"
+)
+
+// maxPDFPageSize is the largest page side, in points, that PDF readers
+// accept (200 inches)
+const maxPDFPageSize = 14400
+
+// page sizes in PDF points (1/72 inch), portrait
+var pdfPageSizes = map[string][2]float64{
+	"a4":     {595.28, 841.89},
+	"letter": {612, 792},
+}
+
+type ImagesToPDFOptions struct {
+	// PageSize: a4, letter or fit (page matches each image)
+	PageSize string
+
+	// Orientation: portrait, landscape or auto (follows each image)
+	Orientation string
+
+	// Margin around each image in points
+	Margin int
+
+	// JPEG quality used when re-encoding images into the PDF
+	Quality int
+}
+
+// ImagesToPDF builds a PDF with one image per page, in the order given
+func ImagesToPDF(inputs []io.Reader, opts ImagesToPDFOptions) ([]byte, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("at least one image is required")
+	}
+
+	opts.PageSize = strings.ToLower(opts.PageSize)
+	if _, ok := pdfPageSizes[opts.PageSize]; !ok && opts.PageSize != "fit" {
+		opts.PageSize = "a4"
+	}
+	if opts.Margin < 0 || opts.Margin > 144 {
+		opts.Margin = 0
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		opts.Quality = 85
+	}
+
+	pdf := newPDFWriter()
+
+	// objects 1 and 2 are the catalog and page tree; pages follow from 3
+	pageRefs := make([]string, 0, len(inputs))
+	for i := range inputs {
+		pageRefs = append(pageRefs, fmt.Sprintf("%d 0 R", 3+i*3))
+	}
+
+	pdf.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	pdf.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageRefs, " "), len(inputs)))
+
+	for i, input := range inputs {
+		img, _, err := decodeImage(input)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i+1, err)
+		}
+
+		// JPEG has no alpha channel, so flatten onto white first
+		bounds := img.Bounds()
+		flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(flat, flat.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+
+		var jpegData bytes.Buffer
+		if err := jpeg.Encode(&jpegData, flat, &jpeg.Options{Quality: opts.Quality}); err != nil {
+			return nil, fmt.Errorf("image %d: failed to encode JPEG: %w", i+1, err)
+		}
+
+		imgW, imgH := float64(bounds.Dx()), float64(bounds.Dy())
+		pageW, pageH := pageDimensions(opts, imgW, imgH)
+
+		// scale to fit inside the margins, centred, never upscaling past 1px = 1pt
+		margin := float64(opts.Margin)
+		scale := min((pageW-2*margin)/imgW, (pageH-2*margin)/imgH, 1)
+		drawW, drawH := imgW*scale, imgH*scale
+		x, y := (pageW-drawW)/2, (pageH-drawH)/2
+
+		pageObj, imageObj, contentObj := 3+i*3, 4+i*3, 5+i*3
+		content := fmt.Sprintf("q %.2f 0 0 %.2f %.2f %.2f cm /Im0 Do Q", drawW, drawH, x, y)
+
+		pdf.object(pageObj, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>",
+			pageW, pageH, imageObj, contentObj))
+		pdf.stream(imageObj, fmt.Sprintf(
+			"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+			bounds.Dx(), bounds.Dy()), jpegData.Bytes())
+		pdf.stream(contentObj, "", []byte(content))
+	}
+
+	return pdf.finish(1), nil
+}
+
+// pageDimensions returns the page width and height in points for an image
+func pageDimensions(opts ImagesToPDFOptions, imgW, imgH float64) (float64, float64) {
+	if opts.PageSize == "fit" {
+		// 1px = 1pt, shrunk if needed so neither side passes the largest
+		// page readers accept
+		margin := float64(opts.Margin)
+		scale := min(1, (maxPDFPageSize-2*margin)/imgW, (maxPDFPageSize-2*margin)/imgH)
+		return imgW*scale + 2*margin, imgH*scale + 2*margin
+	}
+
+	size := pdfPageSizes[opts.PageSize]
+	w, h := size[0], size[1]
+
+	landscape := opts.Orientation == "landscape" ||
+		(opts.Orientation != "portrait" && imgW > imgH)
+	if landscape {
+		w, h = h, w
+	}
+
+	return w, h
+}
+
+// pdfWriter serialises numbered objects and builds the cross-reference
+// table. Objects must be written in ascending order starting from 1.
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	// the binary comment marks the file as containing 8-bit data
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	return w
+}
+
+func (w *pdfWriter) object(num int, body string) {
+	w.offsets = append(w.offsets, w.buf.Len())
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", num, body)
+}
+
+func (w *pdfWriter) stream(num int, dict string, data []byte) {
+	w.offsets = append(w.offsets, w.buf.Len())
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< %s /Length %d >>\nstream\n", num, dict, len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (w *pdfWriter) finish(rootObj int) []byte {
+	xrefOffset := w.buf.Len()
+
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n", len(w.offsets)+1)
+	w.buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, rootObj, xrefOffset)
+
+	return w.buf.Bytes()
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ErrPDFNotSmaller is returned when compression would not shrink the document
+var ErrPDFNotSmaller = errors.New("compressed PDF is not smaller than the original")
+
+// MinPDFImageDPI and MaxPDFImageDPI bound PDFCompressOptions.ImageDPI
+const (
+	MinPDFImageDPI = 36
+	MaxPDFImageDPI = 600
+)
+
+type PDFCompressOptions struct {
+	// Ghostscript PDFSETTINGS preset: screen, ebook, printer or prepress
+	Preset string
+
+	// ImageDPI overrides the preset's image downsampling resolution; 0 keeps it
+	ImageDPI int
+
+	Grayscale bool
+}
+
+type PDFCompressResult struct {
+	Data           []byte `json:"data"`
+	OriginalSize   int64  `json:"original_size"`
+	CompressedSize int64  `json:"compressed_size"`
+}
+
+func CompressPDF(pdfReader io.Reader, opts PDFCompressOptions) (*PDFCompressResult, error) {
+	switch opts.Preset {
+	case "screen", "ebook", "printer", "prepress":
+	default:
+		opts.Preset = "ebook"
+	}
+	if opts.ImageDPI != 0 && (opts.ImageDPI < MinPDFImageDPI || opts.ImageDPI > MaxPDFImageDPI) {
+		return nil, fmt.Errorf("image DPI must be between %d and %d", MinPDFImageDPI, MaxPDFImageDPI)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pdf-compress-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	pdfPath, err := writeTempPDF(tmpDir, "input.pdf", pdfReader)
+	if err != nil {
+		return nil, err
+	}
+
+	inputInfo, err := os.Stat(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check file size: %w", err)
+	}
+
+	outputPath := filepath.Join(tmpDir, "compressed.pdf")
+	args := append(pdfwriteArgs(outputPath),
+		"-dCompatibilityLevel=1.5",
+		"-dPDFSETTINGS=/"+opts.Preset,
+	)
+
+	if opts.ImageDPI > 0 {
+		dpi := strconv.Itoa(opts.ImageDPI)
+		args = append(args,
+			"-dDownsampleColorImages=true",
+			"-dDownsampleGrayImages=true",
+			"-dDownsampleMonoImages=true",
+			"-dColorImageDownsampleType=/Bicubic",
+			"-dGrayImageDownsampleType=/Bicubic",
+			"-dColorImageResolution="+dpi,
+			"-dGrayImageResolution="+dpi,
+			"-dMonoImageResolution="+dpi,
+		)
+	}
+
+	if opts.Grayscale {
+		args = append(args,
+			"-sColorConversionStrategy=Gray",
+			"-dProcessColorModel=/DeviceGray",
+		)
+	}
+
+	args = append(args, pdfPath)
+
+	if _, err := runGhostscript(args...); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compressed PDF: %w", err)
+	}
+
+	if int64(len(data)) >= inputInfo.Size() {
+		return nil, ErrPDFNotSmaller
+	}
+
+	return &PDFCompressResult{
+		Data:           data,
+		OriginalSize:   inputInfo.Size(),
+		CompressedSize: int64(len(data)),
+	}, nil
+}
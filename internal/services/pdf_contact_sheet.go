@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+const (
+	// MaxContactSheetPages keeps the tiled image to a sensible size
+	MaxContactSheetPages = 200
+
+	contactSheetGap = 16
+)
+
+type ContactSheetOptions struct {
+	// Columns in the grid; rows follow from the page count
+	Columns int
+
+	// ThumbWidth is the width of each page tile in pixels
+	ThumbWidth int
+
+	// Format and Quality of the final sheet: jpeg, png or webp
+	Format  string
+	Quality int
+}
+
+// RenderPDFContactSheet tiles every page of the document into a single
+// preview image. Rendering options such as colour mode and anti-aliasing are
+// taken from render; its size and format fields are overridden.
+func RenderPDFContactSheet(ctx context.Context, pdfReader io.Reader, render PDFToImagesOptions, opts ContactSheetOptions) ([]byte, error) {
+	if opts.Columns < 1 || opts.Columns > 12 {
+		opts.Columns = 4
+	}
+	if opts.ThumbWidth < 32 || opts.ThumbWidth > 1024 {
+		opts.ThumbWidth = 240
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		opts.Quality = 85
+	}
+	switch opts.Format {
+	case "jpeg", "jpg", "webp":
+	default:
+		opts.Format = "png"
+	}
+	if render.ColorMode == "transparent" && opts.Format != "png" && opts.Format != "webp" {
+		render.ColorMode = "color"
+	}
+
+	render.Format = "png"
+	render.FitWidth = opts.ThumbWidth
+	render.FitHeight = 0
+
+	// pages may differ in size, so every cell takes the largest thumbnail
+	var thumbs []image.Image
+	var thumbBytes int64
+	cellW, cellH := 0, 0
+
+	err := StreamPDFToImages(ctx, pdfReader, render, func(page PDFPageImage) error {
+		if len(thumbs) >= MaxContactSheetPages {
+			return fmt.Errorf("contact sheets are limited to %d pages", MaxContactSheetPages)
+		}
+
+		if err := checkImageLimits(page.ImageData, "png"); err != nil {
+			return err
+		}
+		config, err := png.DecodeConfig(bytes.NewReader(page.ImageData))
+		if err != nil {
+			return fmt.Errorf("failed to decode page %d: %w", page.PageNumber, err)
+		}
+
+		// the sheet and every thumbnail are held at once, so check the
+		// sheet as it grows rather than after all pages are rendered
+		cellW, cellH = max(cellW, config.Width), max(cellH, config.Height)
+		thumbBytes += int64(config.Width) * int64(config.Height) * 4
+		sheetW, sheetH := contactSheetSize(len(thumbs)+1, opts.Columns, cellW, cellH)
+		if err := checkImageDimensions(sheetW, sheetH); err != nil {
+			return err
+		}
+		if need := thumbBytes + int64(sheetW)*int64(sheetH)*4; need > DefaultImageLimits.MaxDecodeBytes {
+			return fmt.Errorf("%w: a %dx%d contact sheet needs about %d MB, the limit is %d MB",
+				ErrImageTooLarge, sheetW, sheetH, need>>20, DefaultImageLimits.MaxDecodeBytes>>20)
+		}
+
+		thumb, err := png.Decode(bytes.NewReader(page.ImageData))
+		if err != nil {
+			return fmt.Errorf("failed to decode page %d: %w", page.PageNumber, err)
+		}
+
+		thumbs = append(thumbs, thumb)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(thumbs) == 0 {
+		return nil, fmt.Errorf("the PDF has no pages")
+	}
+
+	columns := min(opts.Columns, len(thumbs))
+	sheetW, sheetH := contactSheetSize(len(thumbs), opts.Columns, cellW, cellH)
+	sheet := image.NewRGBA(image.Rect(0, 0, sheetW, sheetH))
+
+	if render.ColorMode != "transparent" {
+		draw.Draw(sheet, sheet.Bounds(), &image.Uniform{C: color.RGBA{R: 229, G: 231, B: 235, A: 255}}, image.Point{}, draw.Src)
+	}
+
+	for i, thumb := range thumbs {
+		col, row := i%columns, i/columns
+		b := thumb.Bounds()
+
+		// centre each page within its cell
+		x := contactSheetGap + col*(cellW+contactSheetGap) + (cellW-b.Dx())/2
+		y := contactSheetGap + row*(cellH+contactSheetGap) + (cellH-b.Dy())/2
+
+		draw.Draw(sheet, image.Rect(x, y, x+b.Dx(), y+b.Dy()), thumb, b.Min, draw.Over)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeImage(&buf, sheet, opts.Format, opts.Quality); err != nil {
+		return nil, fmt.Errorf("failed to encode contact sheet: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// contactSheetSize is the size of a sheet of pages cells laid out in up to
+// columns columns
+func contactSheetSize(pages, columns, cellW, cellH int) (int, int) {
+	columns = min(columns, pages)
+	rows := (pages + columns - 1) / columns
+	return columns*cellW + (columns+1)*contactSheetGap, rows*cellH + (rows+1)*contactSheetGap
+}
@@ -2,23 +2,60 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"image"
 	"image/png"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+)
 
-	"github.com/chai2010/webp"
+var (
+	ErrPDFPasswordRequired = errors.New("this PDF is password protected; please enter its password")
+	ErrPDFWrongPassword    = errors.New("the password for this PDF is incorrect")
 )
 
+// phrases Ghostscript prints when it cannot open an encrypted document
+var ghostscriptPasswordErrors = []string{
+	"requires a password",
+	"password did not work",
+	"invalid password",
+	"incorrect password",
+}
+
 type PDFToImagesOptions struct {
 	DPI       int
 	Format    string
 	Quality   int
 	FirstPage int
 	LastPage  int
+
+	// Password opens encrypted documents. It is passed to Ghostscript only
+	// and is never included in returned errors.
+	Password string
+
+	// ColorMode: color (default), gray or transparent. Transparent keeps
+	// the page background see-through and needs PNG or WebP output.
+	ColorMode string
+
+	// anti-aliasing bits for text and line art: 1 (off), 2 or 4 (default)
+	TextAlphaBits     int
+	GraphicsAlphaBits int
+
+	// FitWidth and FitHeight replace DPI with a pixel bounding box, sized
+	// from the first page. Either may be 0 to constrain one side only.
+	FitWidth  int
+	FitHeight int
+
+	// Watermark is stamped over every page
+	Watermark *Watermark
 }
 
 type PDFPageImage struct {
@@ -27,7 +64,34 @@ type PDFPageImage struct {
 	Format     string
 }
 
+const (
+	// pages rendered by a single Ghostscript process
+	pdfRenderChunkPages = 8
+
+	// upper bound on concurrent Ghostscript processes per conversion
+	maxPDFRenderWorkers = 4
+)
+
 func ConvertPDFToImages(pdfReader io.Reader, opts PDFToImagesOptions) ([]PDFPageImage, error) {
+	var images []PDFPageImage
+
+	err := StreamPDFToImages(context.Background(), pdfReader, opts, func(img PDFPageImage) error {
+		images = append(images, img)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// StreamPDFToImages renders the document in chunks of pages using several
+// Ghostscript processes in parallel and calls emit once per page, in page
+// order. Rendered pages wait on disk rather than in memory and only a few
+// chunks may be rendered ahead of emit, so memory use does not grow with the
+// page count.
+func StreamPDFToImages(ctx context.Context, pdfReader io.Reader, opts PDFToImagesOptions, emit func(PDFPageImage) error) error {
 	if opts.DPI < 72 || opts.DPI > 600 {
 		opts.DPI = 150
 	}
@@ -35,145 +99,410 @@ func ConvertPDFToImages(pdfReader io.Reader, opts PDFToImagesOptions) ([]PDFPage
 		opts.Quality = 85
 	}
 
-	gsPath, err := exec.LookPath("gs")
+	switch opts.Format {
+	case "jpeg", "jpg":
+		opts.Format = "jpeg"
+	case "webp":
+	default:
+		opts.Format = "png"
+	}
+
+	switch opts.ColorMode {
+	case "gray":
+	case "transparent":
+		if opts.Format == "jpeg" {
+			return fmt.Errorf("transparent backgrounds need PNG or WebP output")
+		}
+	default:
+		opts.ColorMode = "color"
+	}
+
+	if opts.TextAlphaBits != 1 && opts.TextAlphaBits != 2 {
+		opts.TextAlphaBits = 4
+	}
+	if opts.GraphicsAlphaBits != 1 && opts.GraphicsAlphaBits != 2 {
+		opts.GraphicsAlphaBits = 4
+	}
+	if opts.FitWidth < 0 || opts.FitWidth > 10000 || opts.FitHeight < 0 || opts.FitHeight > 10000 {
+		return fmt.Errorf("fit size must be between 1 and 10000 pixels")
+	}
+
+	watermark, err := newWatermarker(opts.Watermark)
 	if err != nil {
-		return nil, fmt.Errorf("Ghostscript not found: %w (install with: apt-get install ghostscript)", err)
+		return err
 	}
 
 	tmpDir, err := os.MkdirTemp("", "pdf-convert-*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
 	defer os.RemoveAll(tmpDir)
 
-	pdfPath := filepath.Join(tmpDir, "input.pdf")
-	pdfFile, err := os.Create(pdfPath)
+	pdfPath, err := writeTempPDF(tmpDir, "input.pdf", pdfReader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp PDF: %w", err)
+		return err
 	}
 
-	_, err = io.Copy(pdfFile, pdfReader)
-	pdfFile.Close()
+	pageCount, err := pdfPageCount(pdfPath, opts.Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to write PDF: %w", err)
+		return pdfPasswordError(err, opts.Password)
 	}
 
-	device := "png16m"
-	switch opts.Format {
-	case "jpeg", "jpg":
-		device = "jpeg"
-	case "png":
-		device = "png16m"
-	case "webp":
-		device = "png16m"
+	resolution := strconv.Itoa(opts.DPI)
+	if opts.FitWidth > 0 || opts.FitHeight > 0 {
+		resolution, err = fitResolution(pdfPath, opts)
+		if err != nil {
+			return pdfPasswordError(err, opts.Password)
+		}
+	}
+
+	firstPage, lastPage := 1, pageCount
+	if opts.FirstPage > 0 {
+		firstPage = opts.FirstPage
+	}
+	if opts.LastPage > 0 && opts.LastPage < lastPage {
+		lastPage = opts.LastPage
+	}
+	if firstPage > lastPage {
+		return fmt.Errorf("page range %d-%d is outside the document (1-%d)", firstPage, lastPage, pageCount)
+	}
+
+	var chunks []*pdfRenderChunk
+	for start := firstPage; start <= lastPage; start += pdfRenderChunkPages {
+		chunks = append(chunks, &pdfRenderChunk{
+			first: start,
+			last:  min(start+pdfRenderChunkPages-1, lastPage),
+			dir:   filepath.Join(tmpDir, fmt.Sprintf("chunk-%05d", start)),
+			done:  make(chan struct{}),
+		})
 	}
 
-	outputPattern := filepath.Join(tmpDir, "page-%04d."+opts.Format)
+	workers := min(runtime.NumCPU(), maxPDFRenderWorkers, len(chunks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// ahead limits how many chunks may sit rendered but not yet emitted
+	ahead := make(chan struct{}, workers*2)
+	jobs := make(chan *pdfRenderChunk)
+
+	go func() {
+		defer close(jobs)
+		for _, chunk := range chunks {
+			select {
+			case ahead <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				chunk.err = renderPDFChunk(ctx, pdfPath, chunk, resolution, opts)
+				close(chunk.done)
+			}
+		}()
+	}
+
+	for _, chunk := range chunks {
+		select {
+		case <-chunk.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if chunk.err != nil {
+			return pdfPasswordError(chunk.err, opts.Password)
+		}
+
+		images, err := loadChunkImages(chunk, opts, watermark)
+		if err != nil {
+			return err
+		}
+
+		for _, img := range images {
+			if err := emit(img); err != nil {
+				return err
+			}
+		}
+
+		os.RemoveAll(chunk.dir)
+		<-ahead
+	}
+
+	return nil
+}
+
+type pdfRenderChunk struct {
+	first int
+	last  int
+	dir   string
+	done  chan struct{}
+	err   error
+}
+
+func renderPDFChunk(ctx context.Context, pdfPath string, chunk *pdfRenderChunk, resolution string, opts PDFToImagesOptions) error {
+	if err := os.Mkdir(chunk.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create chunk directory: %w", err)
+	}
+
+	device, ext := renderDevice(opts)
 
 	args := []string{
 		"-dNOPAUSE",
 		"-dBATCH",
 		"-dSAFER",
 		"-sDEVICE=" + device,
-		"-r" + strconv.Itoa(opts.DPI),
-		"-sOutputFile=" + outputPattern,
-		pdfPath,
+		"-r" + resolution,
+		fmt.Sprintf("-dTextAlphaBits=%d", opts.TextAlphaBits),
+		fmt.Sprintf("-dGraphicsAlphaBits=%d", opts.GraphicsAlphaBits),
+		fmt.Sprintf("-dFirstPage=%d", chunk.first),
+		fmt.Sprintf("-dLastPage=%d", chunk.last),
+		"-sOutputFile=" + filepath.Join(chunk.dir, "page-%04d."+ext),
 	}
 
 	if opts.Format == "jpeg" {
-		args = append(args[:6], append([]string{
-			"-dJPEGQ=" + strconv.Itoa(opts.Quality),
-		}, args[6:]...)...)
+		args = append(args, "-dJPEGQ="+strconv.Itoa(opts.Quality))
 	}
-
-	if opts.FirstPage > 0 {
-		pageRange := fmt.Sprintf("-dFirstPage=%d", opts.FirstPage)
-		args = append([]string{args[0]}, append([]string{pageRange}, args[1:]...)...)
+	if opts.Password != "" {
+		args = append(args, "-sPDFPassword="+opts.Password)
 	}
-	if opts.LastPage > 0 {
-		pageRange := fmt.Sprintf("-dLastPage=%d", opts.LastPage)
-		args = append([]string{args[0]}, append([]string{pageRange}, args[1:]...)...)
+
+	args = append(args, pdfPath)
+
+	_, err := runGhostscriptContext(ctx, args...)
+	return err
+}
+
+// renderDevice picks the Ghostscript output device and file extension.
+// WebP is encoded afterwards from lossless PNG renders.
+func renderDevice(opts PDFToImagesOptions) (string, string) {
+	// watermarked JPEG pages are rendered losslessly and encoded afterwards
+	if opts.Format == "jpeg" && opts.Watermark == nil {
+		if opts.ColorMode == "gray" {
+			return "jpeggray", "jpeg"
+		}
+		return "jpeg", "jpeg"
 	}
 
-	cmd := exec.Command(gsPath, args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("ghostscript failed: %w\nOutput: %s", err, string(output))
+	switch opts.ColorMode {
+	case "gray":
+		return "pnggray", "png"
+	case "transparent":
+		return "pngalpha", "png"
+	default:
+		return "png16m", "png"
 	}
+}
 
-	images, err := loadGeneratedImages(tmpDir, opts.Format)
+// fitResolution converts the FitWidth/FitHeight pixel box into a resolution
+// using the first page's size, so every page is rendered at the same scale
+func fitResolution(pdfPath string, opts PDFToImagesOptions) (string, error) {
+	widthPt, heightPt, err := pdfFirstPageSize(pdfPath, opts.Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load images: %w", err)
+		return "", err
 	}
 
-	if opts.Format == "webp" {
-		images, err = convertImagesToWebP(images, opts.Quality)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert to WebP: %w", err)
+	dpi := 0.0
+	if opts.FitWidth > 0 {
+		dpi = float64(opts.FitWidth) * 72 / widthPt
+	}
+	if opts.FitHeight > 0 {
+		byHeight := float64(opts.FitHeight) * 72 / heightPt
+		if dpi == 0 || byHeight < dpi {
+			dpi = byHeight
 		}
 	}
 
-	return images, nil
+	if dpi < 1 || dpi > 1200 {
+		return "", fmt.Errorf("requested size needs %.0f DPI, which is outside 1-1200", dpi)
+	}
+
+	return strconv.FormatFloat(dpi, 'f', 2, 64), nil
 }
 
-func loadGeneratedImages(dir string, format string) ([]PDFPageImage, error) {
-	entries, err := os.ReadDir(dir)
+// pdfFirstPageSize returns the width and height of page 1 in points
+func pdfFirstPageSize(pdfPath string, password string) (float64, float64, error) {
+	args := []string{
+		"-q",
+		"-dNODISPLAY",
+		"-dSAFER",
+		"--permit-file-read=" + pdfPath,
+	}
+	if password != "" {
+		args = append(args, "-sPDFPassword="+password)
+	}
+	args = append(args, "-c", fmt.Sprintf(
+		"(%s) (r) file runpdfbegin 1 pdfgetpage /MediaBox pget pop "+
+			"aload pop exch 4 -1 roll sub = exch sub = quit", pdfPath))
+
+	output, err := runGhostscript(args...)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 
-	var images []PDFPageImage
-	pageNum := 1
+	fields := strings.Fields(string(output))
+	if len(fields) < 2 {
+		return 0, 0, fmt.Errorf("failed to determine page size")
+	}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
+	width, err1 := strconv.ParseFloat(fields[len(fields)-2], 64)
+	height, err2 := strconv.ParseFloat(fields[len(fields)-1], 64)
+	if err1 != nil || err2 != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("failed to determine page size")
+	}
+
+	return width, height, nil
+}
+
+// loadChunkImages reads a rendered chunk back in page order, watermarking
+// and encoding WebP pages concurrently
+func loadChunkImages(chunk *pdfRenderChunk, opts PDFToImagesOptions, watermark *watermarker) ([]PDFPageImage, error) {
+	_, ext := renderDevice(opts)
+
+	images := make([]PDFPageImage, chunk.last-chunk.first+1)
+	errs := make([]error, len(images))
+
+	var wg sync.WaitGroup
+	for i := range images {
+		// Ghostscript numbers output files from 1 within each run
+		path := filepath.Join(chunk.dir, fmt.Sprintf("page-%04d.%s", i+1, ext))
+		pageNum := chunk.first + i
 
-		if filepath.Ext(entry.Name()) == "."+format ||
-			(format == "webp" && filepath.Ext(entry.Name()) == ".png") {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			data, err := os.ReadFile(path)
 			if err != nil {
-				continue
+				errs[i] = fmt.Errorf("failed to load page %d: %w", pageNum, err)
+				return
 			}
 
-			images = append(images, PDFPageImage{
+			if opts.Format == "webp" || watermark != nil {
+				data, err = encodePage(data, opts, watermark)
+				if err != nil {
+					errs[i] = fmt.Errorf("failed to convert page %d: %w", pageNum, err)
+					return
+				}
+			}
+
+			images[i] = PDFPageImage{
 				PageNumber: pageNum,
 				ImageData:  data,
-				Format:     format,
-			})
-			pageNum++
+				Format:     opts.Format,
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	return images, nil
 }
 
-func convertImagesToWebP(images []PDFPageImage, quality int) ([]PDFPageImage, error) {
-	var converted []PDFPageImage
+// runGhostscript is the single entry point for every Ghostscript invocation so
+// that all PDF tools share the same binary lookup and error reporting
+func runGhostscript(args ...string) ([]byte, error) {
+	return runGhostscriptContext(context.Background(), args...)
+}
 
-	for _, img := range images {
-		imgReader := bytes.NewReader(img.ImageData)
-		decodedImg, err := png.Decode(imgReader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode PNG: %w", err)
+// runGhostscriptContext is runGhostscript with the process killed when ctx
+// is cancelled
+func runGhostscriptContext(ctx context.Context, args ...string) ([]byte, error) {
+	gsPath, err := exec.LookPath("gs")
+	if err != nil {
+		return nil, fmt.Errorf("Ghostscript not found: %w (install with: apt-get install ghostscript)", err)
+	}
+
+	cmd := exec.CommandContext(ctx, gsPath, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		lower := strings.ToLower(string(output))
+		for _, phrase := range ghostscriptPasswordErrors {
+			if strings.Contains(lower, phrase) {
+				return nil, ErrPDFPasswordRequired
+			}
 		}
 
-		var buf bytes.Buffer
-		err = webp.Encode(&buf, decodedImg, &webp.Options{
-			Quality: float32(quality),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to encode WebP: %w", err)
+		return nil, fmt.Errorf("ghostscript failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return output, nil
+}
+
+// pdfPasswordError turns a failed unlock into ErrPDFWrongPassword when a
+// password was supplied, and scrubs the password from any other error so it
+// cannot reach logs or the audit table
+func pdfPasswordError(err error, password string) error {
+	if password == "" {
+		return err
+	}
+
+	if errors.Is(err, ErrPDFPasswordRequired) {
+		return ErrPDFWrongPassword
+	}
+
+	return errors.New(strings.ReplaceAll(err.Error(), password, "[redacted]"))
+}
+
+func writeTempPDF(dir string, name string, r io.Reader) (string, error) {
+	pdfPath := filepath.Join(dir, name)
+	pdfFile, err := os.Create(pdfPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp PDF: %w", err)
+	}
+
+	_, err = io.Copy(pdfFile, r)
+	pdfFile.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to write PDF: %w", err)
+	}
+
+	return pdfPath, nil
+}
+
+// encodePage re-encodes a page Ghostscript rendered as PNG, for output
+// formats and watermarks Ghostscript can't produce itself
+func encodePage(pngData []byte, opts PDFToImagesOptions, watermark *watermarker) ([]byte, error) {
+	// high DPI renders of large pages can exceed the image limits
+	if err := checkImageLimits(pngData, "png"); err != nil {
+		return nil, err
+	}
+
+	decodedImg, err := png.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PNG: %w", err)
+	}
+
+	var img image.Image = decodedImg
+	if watermark != nil {
+		if img, err = watermark.apply(img); err != nil {
+			return nil, err
 		}
+	}
 
-		converted = append(converted, PDFPageImage{
-			PageNumber: img.PageNumber,
-			ImageData:  buf.Bytes(),
-			Format:     "webp",
-		})
+	var buf bytes.Buffer
+	if err := encodeImage(&buf, img, opts.Format, opts.Quality); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", opts.Format, err)
 	}
 
-	return converted, nil
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,465 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MaxOrganizerPages caps how many pages a single merge/split/reorder may touch
+const MaxOrganizerPages = 2000
+
+// ErrInvalidPageSelection is returned when a page spec cannot be parsed, is
+// outside the document or selects no pages or too many
+var ErrInvalidPageSelection = errors.New("invalid page selection")
+
+type PDFPageRange struct {
+	Start int
+	End   int
+}
+
+// PDFPage identifies one page of one of the uploaded documents
+type PDFPage struct {
+	Document int // index into the uploaded documents
+	Page     int // 1-based
+	Rotate   int // 0, 90, 180 or 270, added clockwise to the page's own rotation
+}
+
+// keepPageRotation tells renderPDFSegment to leave each page's /Rotate as it
+// is in the source document
+const keepPageRotation = -1
+
+type PDFPart struct {
+	Name string
+	Data []byte
+}
+
+// MergePDFs concatenates the documents in the order given
+func MergePDFs(inputs []io.Reader) ([]byte, error) {
+	if len(inputs) < 2 {
+		return nil, fmt.Errorf("at least two PDFs are required to merge")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pdf-merge-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	paths := make([]string, 0, len(inputs))
+	totalPages := 0
+	for i, input := range inputs {
+		path, err := writeTempPDF(tmpDir, fmt.Sprintf("input-%03d.pdf", i), input)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+
+		pageCount, err := pdfPageCount(path, "")
+		if err != nil {
+			return nil, err
+		}
+		if totalPages += pageCount; totalPages > MaxOrganizerPages {
+			return nil, fmt.Errorf("%w: the merged document would have more than %d pages", ErrInvalidPageSelection, MaxOrganizerPages)
+		}
+	}
+
+	outputPath := filepath.Join(tmpDir, "merged.pdf")
+	args := append(pdfwriteArgs(outputPath), paths...)
+	if _, err := runGhostscript(args...); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(outputPath)
+}
+
+// SplitPDF produces one document per range in spec, e.g. "1-3,4,5-".
+// An empty spec splits the document into single pages.
+func SplitPDF(input io.Reader, spec string) ([]PDFPart, error) {
+	tmpDir, err := os.MkdirTemp("", "pdf-split-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	pdfPath, err := writeTempPDF(tmpDir, "input.pdf", input)
+	if err != nil {
+		return nil, err
+	}
+
+	pageCount, err := pdfPageCount(pdfPath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []PDFPageRange
+	if strings.TrimSpace(spec) == "" {
+		for page := 1; page <= pageCount; page++ {
+			ranges = append(ranges, PDFPageRange{Start: page, End: page})
+		}
+	} else {
+		ranges, err = ParsePageRanges(spec, pageCount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parts := make([]PDFPart, 0, len(ranges))
+	for i, pr := range ranges {
+		outputPath := filepath.Join(tmpDir, fmt.Sprintf("part-%03d.pdf", i+1))
+		if err := renderPDFSegment(pdfPath, outputPath, pr, keepPageRotation); err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read split part: %w", err)
+		}
+
+		name := fmt.Sprintf("pages-%d-%d.pdf", pr.Start, pr.End)
+		if pr.Start == pr.End {
+			name = fmt.Sprintf("page-%d.pdf", pr.Start)
+		}
+
+		parts = append(parts, PDFPart{Name: name, Data: data})
+	}
+
+	return parts, nil
+}
+
+// RotatePDFPages rotates the pages selected by spec clockwise by angle
+// degrees. An empty spec rotates every page.
+func RotatePDFPages(input io.Reader, spec string, angle int) ([]byte, error) {
+	angle = ((angle % 360) + 360) % 360
+	if angle%90 != 0 {
+		return nil, fmt.Errorf("rotation must be a multiple of 90 degrees")
+	}
+
+	return organizePDF(input, func(pageCount int) ([]PDFPage, error) {
+		selected := make(map[int]bool)
+		if strings.TrimSpace(spec) == "" {
+			for page := 1; page <= pageCount; page++ {
+				selected[page] = true
+			}
+		} else {
+			pageList, err := ParsePageList(spec, pageCount)
+			if err != nil {
+				return nil, err
+			}
+			for _, page := range pageList {
+				selected[page] = true
+			}
+		}
+
+		pages := make([]PDFPage, 0, pageCount)
+		for page := 1; page <= pageCount; page++ {
+			rotate := 0
+			if selected[page] {
+				rotate = angle
+			}
+			pages = append(pages, PDFPage{Page: page, Rotate: rotate})
+		}
+
+		return pages, nil
+	})
+}
+
+// ReorderPDFPages rebuilds the document with pages in the order listed in
+// spec, e.g. "3,1-2,5". Pages that are not listed are deleted.
+func ReorderPDFPages(input io.Reader, spec string) ([]byte, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, fmt.Errorf("%w: page order cannot be empty", ErrInvalidPageSelection)
+	}
+
+	return organizePDF(input, func(pageCount int) ([]PDFPage, error) {
+		pageList, err := ParsePageList(spec, pageCount)
+		if err != nil {
+			return nil, err
+		}
+
+		pages := make([]PDFPage, 0, len(pageList))
+		for _, page := range pageList {
+			pages = append(pages, PDFPage{Page: page})
+		}
+
+		return pages, nil
+	})
+}
+
+// organizePDF writes input to disk, asks plan for the resulting page
+// sequence and assembles it
+func organizePDF(input io.Reader, plan func(pageCount int) ([]PDFPage, error)) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "pdf-organize-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	pdfPath, err := writeTempPDF(tmpDir, "input.pdf", input)
+	if err != nil {
+		return nil, err
+	}
+
+	pageCount, err := pdfPageCount(pdfPath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := plan(pageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return assemblePDF(tmpDir, []string{pdfPath}, pages)
+}
+
+// assemblePDF builds a document from an arbitrary page sequence. Ghostscript
+// can only emit contiguous page ranges, so the sequence is cut into runs of
+// consecutive pages sharing the same rotation, each run is rendered on its
+// own and the runs are then concatenated.
+func assemblePDF(tmpDir string, docs []string, pages []PDFPage) ([]byte, error) {
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("%w: the resulting document would have no pages", ErrInvalidPageSelection)
+	}
+	if len(pages) > MaxOrganizerPages {
+		return nil, fmt.Errorf("%w: too many pages (%d), maximum is %d", ErrInvalidPageSelection, len(pages), MaxOrganizerPages)
+	}
+
+	type segment struct {
+		doc    int
+		pages  PDFPageRange
+		rotate int
+	}
+
+	// rotations are relative to each page's own /Rotate, which is only
+	// looked up for documents with pages to rotate
+	existing := make(map[int][]int)
+
+	var segments []segment
+	for _, p := range pages {
+		if p.Document < 0 || p.Document >= len(docs) {
+			return nil, fmt.Errorf("invalid document index: %d", p.Document)
+		}
+
+		rotate := keepPageRotation
+		if p.Rotate%360 != 0 {
+			rotations, ok := existing[p.Document]
+			if !ok {
+				var err error
+				if rotations, err = pdfPageRotations(docs[p.Document]); err != nil {
+					return nil, err
+				}
+				existing[p.Document] = rotations
+			}
+			if p.Page < 1 || p.Page > len(rotations) {
+				return nil, fmt.Errorf("page %d is outside the document", p.Page)
+			}
+			rotate = ((rotations[p.Page-1]+p.Rotate)%360 + 360) % 360
+		}
+
+		if n := len(segments); n > 0 {
+			last := &segments[n-1]
+			if last.doc == p.Document && last.rotate == rotate && last.pages.End+1 == p.Page {
+				last.pages.End = p.Page
+				continue
+			}
+		}
+
+		segments = append(segments, segment{
+			doc:    p.Document,
+			pages:  PDFPageRange{Start: p.Page, End: p.Page},
+			rotate: rotate,
+		})
+	}
+
+	segmentPaths := make([]string, 0, len(segments))
+	for i, seg := range segments {
+		outputPath := filepath.Join(tmpDir, fmt.Sprintf("segment-%04d.pdf", i))
+		if err := renderPDFSegment(docs[seg.doc], outputPath, seg.pages, seg.rotate); err != nil {
+			return nil, err
+		}
+		segmentPaths = append(segmentPaths, outputPath)
+	}
+
+	if len(segmentPaths) == 1 {
+		return os.ReadFile(segmentPaths[0])
+	}
+
+	outputPath := filepath.Join(tmpDir, "assembled.pdf")
+	args := append(pdfwriteArgs(outputPath), segmentPaths...)
+	if _, err := runGhostscript(args...); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(outputPath)
+}
+
+// renderPDFSegment copies a contiguous page range into a new document,
+// setting every page's /Rotate to rotate unless it is keepPageRotation
+func renderPDFSegment(pdfPath, outputPath string, pages PDFPageRange, rotate int) error {
+	args := pdfwriteArgs(outputPath)
+	args = append(args,
+		fmt.Sprintf("-dFirstPage=%d", pages.Start),
+		fmt.Sprintf("-dLastPage=%d", pages.End),
+	)
+
+	if rotate != keepPageRotation {
+		// The /PAGES pdfmark sets /Rotate in each page dictionary, which the
+		// PDF spec defines as clockwise, so the angle is used as is and the
+		// page content is left untouched
+		args = append(args,
+			"-c", fmt.Sprintf("[/Rotate %d /PAGES pdfmark", rotate),
+			"-f",
+		)
+	}
+
+	args = append(args, pdfPath)
+
+	_, err := runGhostscript(args...)
+	return err
+}
+
+// pdfwriteArgs starts every pdfwrite pass. AutoRotatePages is off so
+// pdfwrite keeps each page's /Rotate rather than replacing it based on the
+// text direction, which would undo rotations when segments are joined.
+func pdfwriteArgs(outputPath string) []string {
+	return []string{
+		"-dNOPAUSE",
+		"-dBATCH",
+		"-dSAFER",
+		"-sDEVICE=pdfwrite",
+		"-dAutoRotatePages=/None",
+		"-sOutputFile=" + outputPath,
+	}
+}
+
+// pdfPageRotations asks Ghostscript for the /Rotate of every page, including
+// values inherited from the page tree, normalised to 0, 90, 180 or 270
+func pdfPageRotations(pdfPath string) ([]int, error) {
+	// pget resolves inherited values in the PostScript PDF interpreter; the
+	// newer interpreter returns pages with them already resolved
+	script := fmt.Sprintf(`(%s) (r) file runpdfbegin
+1 1 pdfpagecount {
+  pdfgetpage
+  /pget where { pop /Rotate pget not { 0 } if } { dup /Rotate known { /Rotate get } { pop 0 } ifelse } ifelse
+  cvi =
+} for
+quit`, pdfPath)
+
+	output, err := runGhostscript("-q", "-dNODISPLAY", "-dSAFER", "--permit-file-read="+pdfPath, "-c", script)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ghostscript may print warnings between the values
+	var rotations []int
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		rotate, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil {
+			continue
+		}
+		rotations = append(rotations, ((rotate/90*90)%360+360)%360)
+	}
+
+	return rotations, nil
+}
+
+// pdfPageCount asks Ghostscript for the number of pages in the document
+func pdfPageCount(pdfPath string, password string) (int, error) {
+	args := []string{
+		"-q",
+		"-dNODISPLAY",
+		"-dSAFER",
+		"--permit-file-read=" + pdfPath,
+	}
+	if password != "" {
+		args = append(args, "-sPDFPassword="+password)
+	}
+	args = append(args, "-c", fmt.Sprintf("(%s) (r) file runpdfbegin pdfpagecount = quit", pdfPath))
+
+	output, err := runGhostscript(args...)
+	if err != nil {
+		return 0, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	count, err := strconv.Atoi(strings.TrimSpace(lines[len(lines)-1]))
+	if err != nil || count < 1 {
+		return 0, fmt.Errorf("failed to determine page count")
+	}
+
+	return count, nil
+}
+
+// ParsePageRanges parses a comma separated list of pages and ranges such as
+// "1-3,5,8-". Open ended ranges run to the last page.
+func ParsePageRanges(spec string, pageCount int) ([]PDFPageRange, error) {
+	var ranges []PDFPageRange
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var pr PDFPageRange
+		var err error
+
+		if before, after, found := strings.Cut(part, "-"); found {
+			pr.Start = 1
+			if s := strings.TrimSpace(before); s != "" {
+				if pr.Start, err = strconv.Atoi(s); err != nil {
+					return nil, fmt.Errorf("%w: %q is not a page range", ErrInvalidPageSelection, part)
+				}
+			}
+			pr.End = pageCount
+			if s := strings.TrimSpace(after); s != "" {
+				if pr.End, err = strconv.Atoi(s); err != nil {
+					return nil, fmt.Errorf("%w: %q is not a page range", ErrInvalidPageSelection, part)
+				}
+			}
+		} else {
+			if pr.Start, err = strconv.Atoi(part); err != nil {
+				return nil, fmt.Errorf("%w: %q is not a page number", ErrInvalidPageSelection, part)
+			}
+			pr.End = pr.Start
+		}
+
+		if pr.Start < 1 || pr.End > pageCount || pr.Start > pr.End {
+			return nil, fmt.Errorf("%w: %s is outside the document (1-%d)", ErrInvalidPageSelection, part, pageCount)
+		}
+
+		ranges = append(ranges, pr)
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("%w: no pages selected", ErrInvalidPageSelection)
+	}
+
+	return ranges, nil
+}
+
+// ParsePageList expands a page range spec into individual page numbers,
+// keeping the order in which they were written
+func ParsePageList(spec string, pageCount int) ([]int, error) {
+	ranges, err := ParsePageRanges(spec, pageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []int
+	for _, pr := range ranges {
+		for page := pr.Start; page <= pr.End; page++ {
+			pages = append(pages, page)
+		}
+		if len(pages) > MaxOrganizerPages {
+			return nil, fmt.Errorf("%w: too many pages selected, maximum is %d", ErrInvalidPageSelection, MaxOrganizerPages)
+		}
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParsePageRanges(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []PDFPageRange
+		wantErr bool
+	}{
+		{spec: "1-3,5,8-", want: []PDFPageRange{{1, 3}, {5, 5}, {8, 10}}},
+		{spec: "-3", want: []PDFPageRange{{1, 3}}},
+		{spec: "-", want: []PDFPageRange{{1, 10}}},
+		{spec: " 4 - 6 , ,10", want: []PDFPageRange{{4, 6}, {10, 10}}},
+		{spec: "7,2", want: []PDFPageRange{{7, 7}, {2, 2}}},
+		{spec: "", wantErr: true},
+		{spec: ",", wantErr: true},
+		{spec: "0", wantErr: true},
+		{spec: "11", wantErr: true},
+		{spec: "5-3", wantErr: true},
+		{spec: "8-12", wantErr: true},
+		{spec: "x", wantErr: true},
+		{spec: "1-y", wantErr: true},
+		{spec: "1-2-3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePageRanges(tt.spec, 10)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidPageSelection) {
+				t.Errorf("ParsePageRanges(%q) = %v, %v; want ErrInvalidPageSelection", tt.spec, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePageRanges(%q): %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePageRanges(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParsePageList(t *testing.T) {
+	got, err := ParsePageList("3-1", 5)
+	if !errors.Is(err, ErrInvalidPageSelection) {
+		t.Errorf("ParsePageList(\"3-1\") = %v, %v; want ErrInvalidPageSelection", got, err)
+	}
+
+	got, err = ParsePageList("4-5,1,2-3,1", 5)
+	if err != nil {
+		t.Fatalf("ParsePageList: %v", err)
+	}
+	if want := []int{4, 5, 1, 2, 3, 1}; !slices.Equal(got, want) {
+		t.Errorf("ParsePageList = %v, want %v", got, want)
+	}
+
+	spec := fmt.Sprintf("1-%d,1", MaxOrganizerPages)
+	if _, err := ParsePageList(spec, MaxOrganizerPages); !errors.Is(err, ErrInvalidPageSelection) {
+		t.Errorf("selecting %d pages: got %v, want ErrInvalidPageSelection", MaxOrganizerPages+1, err)
+	}
+}
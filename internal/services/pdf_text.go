@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type PDFTextOptions struct {
+	FirstPage int
+	LastPage  int
+}
+
+type PDFPageText struct {
+	PageNumber int    `json:"page"`
+	Text       string `json:"text"`
+
+	// Empty is set when no text was found, which usually means the page
+	// is a scanned image
+	Empty bool `json:"empty"`
+}
+
+// ExtractPDFText pulls the text layer out of each page using Ghostscript's
+// txtwrite device
+func ExtractPDFText(pdfReader io.Reader, opts PDFTextOptions) ([]PDFPageText, error) {
+	if opts.FirstPage < 0 || opts.LastPage < 0 {
+		return nil, fmt.Errorf("page numbers must be positive")
+	}
+	if opts.FirstPage > 0 && opts.LastPage > 0 && opts.FirstPage > opts.LastPage {
+		return nil, fmt.Errorf("first page must not be after last page")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "pdf-text-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	pdfPath, err := writeTempPDF(tmpDir, "input.pdf", pdfReader)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"-dNOPAUSE",
+		"-dBATCH",
+		"-dSAFER",
+		"-sDEVICE=txtwrite",
+		"-sOutputFile=" + filepath.Join(tmpDir, "page-%04d.txt"),
+	}
+
+	if opts.FirstPage > 0 {
+		args = append(args, fmt.Sprintf("-dFirstPage=%d", opts.FirstPage))
+	}
+	if opts.LastPage > 0 {
+		args = append(args, fmt.Sprintf("-dLastPage=%d", opts.LastPage))
+	}
+
+	args = append(args, pdfPath)
+
+	if _, err := runGhostscript(args...); err != nil {
+		return nil, err
+	}
+
+	// output files are numbered from 1 regardless of FirstPage
+	firstPage := max(opts.FirstPage, 1)
+
+	var pages []PDFPageText
+	for i := 1; ; i++ {
+		data, err := os.ReadFile(filepath.Join(tmpDir, fmt.Sprintf("page-%04d.txt", i)))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read extracted text: %w", err)
+		}
+
+		text := strings.TrimRight(string(data), " \t\r\n")
+		pages = append(pages, PDFPageText{
+			PageNumber: firstPage + i - 1,
+			Text:       text,
+			Empty:      strings.TrimSpace(text) == "",
+		})
+	}
+
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages were extracted")
+	}
+
+	return pages, nil
+}
+
+// FormatPDFText joins extracted pages into a single document, marking page
+// breaks. format is "markdown" or plain text.
+func FormatPDFText(pages []PDFPageText, format string) string {
+	var sb strings.Builder
+
+	for i, page := range pages {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+
+		if format == "markdown" {
+			fmt.Fprintf(&sb, "## Page %d\n\n", page.PageNumber)
+		} else {
+			fmt.Fprintf(&sb, "--- Page %d ---\n\n", page.PageNumber)
+		}
+
+		if page.Empty {
+			sb.WriteString("[no text found - this page may be a scanned image]")
+			continue
+		}
+
+		sb.WriteString(page.Text)
+	}
+
+	sb.WriteString("\n")
+
+	return sb.String()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
 	"image/png"
 
 	"github.com/skip2/go-qrcode"
@@ -23,6 +24,10 @@ type QRCodeOptions struct {
 	ForegroundColor color.Color
 
 	BackgroundColor color.Color
+
+	// Logo is an optional image drawn over the centre of the code. It hides
+	// some of the modules, so the error correction is raised to at least High.
+	Logo []byte
 }
 
 func GenerateQRCode(opts QRCodeOptions) ([]byte, error) {
@@ -42,18 +47,34 @@ func GenerateQRCode(opts QRCodeOptions) ([]byte, error) {
 		opts.ErrorCorrection = qrcode.Medium
 	}
 
+	// Decode the logo first so a bad upload fails before any drawing
+	var logo image.Image
+	if len(opts.Logo) > 0 {
+		var err error
+		if logo, _, err = decodeImage(bytes.NewReader(opts.Logo)); err != nil {
+			return nil, fmt.Errorf("failed to decode logo: %w", err)
+		}
+		opts.ErrorCorrection = max(opts.ErrorCorrection, qrcode.High)
+	}
+
 	qr, err := qrcode.New(opts.Content, opts.ErrorCorrection)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create QR code: %w", err)
 	}
 
-	if opts.ForegroundColor != nil || opts.BackgroundColor != nil {
+	if opts.ForegroundColor != nil || opts.BackgroundColor != nil || logo != nil {
 		img := qr.Image(opts.Size)
 
 		if opts.ForegroundColor != nil || opts.BackgroundColor != nil {
 			img = applyCustomColors(img, opts.ForegroundColor, opts.BackgroundColor)
 		}
 
+		if logo != nil {
+			if img, err = embedLogo(img, logo, opts.BackgroundColor); err != nil {
+				return nil, err
+			}
+		}
+
 		var buf bytes.Buffer
 		if err := png.Encode(&buf, img); err != nil {
 			return nil, fmt.Errorf("failed to encode PNG: %w", err)
@@ -96,7 +117,7 @@ func applyCustomColors(img image.Image, fg, bg color.Color) image.Image {
 }
 
 // GenerateWiFiQRCode creates a QR code for Wi-Fi credentials
-func GenerateWiFiQRCode(ssid, password, encryption string, size int) ([]byte, error) {
+func GenerateWiFiQRCode(ssid, password, encryption string, size int, logo []byte) ([]byte, error) {
 	// Validate encryption type
 	validEncryption := map[string]bool{
 		"WPA":    true,
@@ -116,11 +137,12 @@ func GenerateWiFiQRCode(ssid, password, encryption string, size int) ([]byte, er
 		Content:         content,
 		Size:            size,
 		ErrorCorrection: qrcode.High,
+		Logo:            logo,
 	})
 }
 
 // vCard is the standard format for contact info
-func GenerateVCardQRCode(name, phone, email string, size int) ([]byte, error) {
+func GenerateVCardQRCode(name, phone, email string, size int, logo []byte) ([]byte, error) {
 	// Build a simple vCard (version 3.0)
 	// vCard has a specific format that contact apps understand
 	content := fmt.Sprintf(`BEGIN:VCARD
@@ -134,25 +156,43 @@ func GenerateVCardQRCode(name, phone, email string, size int) ([]byte, error) {
 		Content:         content,
 		Size:            size,
 		ErrorCorrection: qrcode.Medium,
+		Logo:            logo,
 	})
 }
 
-// func EmbedLogo(qrImage image.Image, logoImage image.Image) image.Image {
-//     bounds := qrImage.Bounds()
-//     result := image.NewRGBA(bounds)
+// embedLogo scales the logo to fit a fifth of the code's width and draws it
+// centred on a padded square of the background colour
+func embedLogo(qrImage image.Image, logo image.Image, bg color.Color) (image.Image, error) {
+	if bg == nil {
+		bg = color.White
+	}
 
-//     // Draw the QR code
-//     draw.Draw(result, bounds, qrImage, image.Point{}, draw.Src)
+	bounds := qrImage.Bounds()
+	result := image.NewRGBA(bounds)
+	draw.Draw(result, bounds, qrImage, bounds.Min, draw.Src)
+
+	// Keep the logo's aspect ratio inside the square
+	box := bounds.Dx() / 5
+	lb := logo.Bounds()
+	w, h := box, box
+	if lb.Dx() > lb.Dy() {
+		h = max(1, box*lb.Dy()/lb.Dx())
+	} else {
+		w = max(1, box*lb.Dx()/lb.Dy())
+	}
 
-//     logoSize := bounds.Dx() / 5
-//     logoPos := image.Rect(
-//         (bounds.Dx()-logoSize)/2,
-//         (bounds.Dy()-logoSize)/2,
-//         (bounds.Dx()+logoSize)/2,
-//         (bounds.Dy()+logoSize)/2,
-//     )
+	scaled, err := scaleImage(logo, w, h)
+	if err != nil {
+		return nil, err
+	}
+
+	centre := image.Pt(bounds.Dx()/2, bounds.Dy()/2)
+	pad := box / 10
+	backing := image.Rect(centre.X-box/2-pad, centre.Y-box/2-pad, centre.X+box/2+pad, centre.Y+box/2+pad)
+	draw.Draw(result, backing, image.NewUniform(bg), image.Point{}, draw.Src)
 
-//     draw.Draw(result, logoPos, logoImage, image.Point{}, draw.Over)
+	logoRect := image.Rect(centre.X-w/2, centre.Y-h/2, centre.X-w/2+w, centre.Y-h/2+h)
+	draw.Draw(result, logoRect, scaled, image.Point{}, draw.Over)
 
-//     return result
-// }
+	return result, nil
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultResponsiveWidths are the breakpoints used when none are given
+var DefaultResponsiveWidths = []int{320, 640, 1024, 1920}
+
+// responsiveFormats are the formats a responsive set may use, in the order
+// browsers should try them
+var responsiveFormats = []string{"avif", "webp", "jpeg", "png"}
+
+// lqipWidth is the width of the inline placeholder image
+const lqipWidth = 24
+
+// MaxResponsiveWidths caps how many widths one request may generate; every
+// width is resized and encoded once per format
+const MaxResponsiveWidths = 12
+
+type ResponsiveImageOptions struct {
+	// Widths to generate; widths larger than the source become the source
+	// width
+	Widths []int
+
+	// Formats to generate each width in, from "avif", "webp", "jpeg" and
+	// "png". Defaults to WebP with a JPEG fallback.
+	Formats []string
+
+	Quality int
+
+	// BaseName prefixes every file name, e.g. "hero" gives hero-640.webp
+	BaseName string
+
+	// Sizes is the sizes attribute of the snippet, "100vw" by default
+	Sizes string
+
+	Alt string
+}
+
+// ResponsiveImage is one generated variant
+type ResponsiveImage struct {
+	File   string `json:"file"`
+	Format string `json:"format"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int    `json:"size"`
+}
+
+// ResponsiveImageManifest describes the set and is written as manifest.json
+type ResponsiveImageManifest struct {
+	Source struct {
+		Format string `json:"format"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"source"`
+	Images   []ResponsiveImage `json:"images"`
+	Sizes    string            `json:"sizes"`
+	BlurHash string            `json:"blurhash"`
+	LQIP     string            `json:"lqip"`
+	HTML     string            `json:"html"`
+}
+
+type ResponsiveImageSet struct {
+	Files    []ArchiveEntry
+	Manifest ResponsiveImageManifest
+}
+
+// GenerateResponsiveImages resizes one upload to several widths in several
+// formats with ConvertImage and builds the matching <picture> markup, a
+// BlurHash and a tiny blurred data URI to show while the real image loads
+func GenerateResponsiveImages(input io.Reader, opts ResponsiveImageOptions) (*ResponsiveImageSet, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	if len(opts.Widths) == 0 {
+		opts.Widths = DefaultResponsiveWidths
+	}
+	if len(opts.Widths) > MaxResponsiveWidths {
+		return nil, fmt.Errorf("at most %d widths can be generated at once", MaxResponsiveWidths)
+	}
+	if len(opts.Formats) == 0 {
+		opts.Formats = []string{"webp", "jpeg"}
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		opts.Quality = 80
+	}
+	if opts.Sizes == "" {
+		opts.Sizes = "100vw"
+	}
+	opts.BaseName = responsiveBaseName(opts.BaseName)
+
+	var formats []string
+	for _, format := range responsiveFormats {
+		for _, f := range opts.Formats {
+			if f = strings.ToLower(f); f == "jpg" {
+				f = "jpeg"
+			}
+			if f == format && !slices.Contains(formats, format) {
+				formats = append(formats, format)
+			}
+		}
+	}
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("choose at least one of AVIF, WebP, JPEG or PNG")
+	}
+
+	src, sourceFormat, err := decodeImage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+
+	// never upscale; wider breakpoints are replaced by the source width
+	var widths []int
+	for _, w := range opts.Widths {
+		w = min(w, srcW, MaxImageDimension)
+		if w > 0 && !slices.Contains(widths, w) {
+			widths = append(widths, w)
+		}
+	}
+	if len(widths) == 0 {
+		return nil, fmt.Errorf("no valid widths given")
+	}
+	slices.Sort(widths)
+
+	set := &ResponsiveImageSet{}
+	m := &set.Manifest
+	m.Source.Format = sourceFormat
+	m.Source.Width = srcW
+	m.Source.Height = srcH
+	m.Sizes = opts.Sizes
+
+	for _, format := range formats {
+		_, ext := ImageContentType(format)
+
+		for _, w := range widths {
+			var buf bytes.Buffer
+			err := ConvertImage(bytes.NewReader(data), &buf, ImageConvertOptions{
+				OutputFormat: format,
+				Quality:      opts.Quality,
+				Operations:   []ImageOperation{{Op: "resize", Mode: "exact", Width: w}},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create %dpx %s: %w", w, format, err)
+			}
+
+			name := fmt.Sprintf("%s-%d.%s", opts.BaseName, w, ext)
+			set.Files = append(set.Files, ArchiveEntry{Name: name, Data: buf.Bytes()})
+			m.Images = append(m.Images, ResponsiveImage{
+				File:   name,
+				Format: format,
+				Width:  w,
+				// matches the exact resize with a missing height
+				Height: max(1, int(math.Round(float64(srcH)*float64(w)/float64(srcW)))),
+				Size:   buf.Len(),
+			})
+		}
+	}
+
+	xComponents, yComponents := 4, 3
+	if srcH > srcW {
+		xComponents, yComponents = 3, 4
+	}
+	if m.BlurHash, err = BlurHash(src, xComponents, yComponents); err != nil {
+		return nil, err
+	}
+	if m.LQIP, err = lqipDataURI(src); err != nil {
+		return nil, err
+	}
+
+	m.HTML = responsiveSnippet(m.Images, formats, opts)
+	set.Files = append(set.Files, ArchiveEntry{Name: "picture.html", Data: []byte(m.HTML)})
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	set.Files = append(set.Files, ArchiveEntry{Name: "manifest.json", Data: manifest})
+
+	return set, nil
+}
+
+// responsiveBaseName reduces a file name to something safe to use in file
+// names and URLs
+func responsiveBaseName(name string) string {
+	name = strings.TrimSuffix(path.Base(name), path.Ext(name))
+
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			sb.WriteRune(r)
+		case r == ' ' || r == '.':
+			sb.WriteRune('-')
+		}
+	}
+
+	if base := strings.Trim(sb.String(), "-"); base != "" {
+		return base
+	}
+	return "image"
+}
+
+// lqipDataURI returns a tiny JPEG as a data URI, meant to be shown scaled up
+// with a CSS blur. JPEG has no alpha, so transparent areas come out black.
+func lqipDataURI(img image.Image) (string, error) {
+	b := img.Bounds()
+	h := max(1, int(math.Round(float64(b.Dy())*lqipWidth/float64(b.Dx()))))
+
+	small, err := scaleImage(img, min(lqipWidth, b.Dx()), min(h, b.Dy()))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, small, &jpeg.Options{Quality: 50}); err != nil {
+		return "", err
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// responsiveSnippet builds a <picture> with one <source> per modern format
+// and an <img> in the most compatible one as the fallback
+func responsiveSnippet(images []ResponsiveImage, formats []string, opts ResponsiveImageOptions) string {
+	srcset := func(format string) (string, ResponsiveImage) {
+		var parts []string
+		var largest ResponsiveImage
+		for _, img := range images {
+			if img.Format == format {
+				parts = append(parts, fmt.Sprintf("%s %dw", img.File, img.Width))
+				largest = img
+			}
+		}
+		return strings.Join(parts, ", "), largest
+	}
+
+	fallback := formats[len(formats)-1]
+	sizes := html.EscapeString(opts.Sizes)
+
+	var sb strings.Builder
+	sb.WriteString("<picture>\n")
+	for _, format := range formats[:len(formats)-1] {
+		contentType, _ := ImageContentType(format)
+		set, _ := srcset(format)
+		fmt.Fprintf(&sb, "  <source type=\"%s\" srcset=\"%s\" sizes=\"%s\">\n", contentType, set, sizes)
+	}
+
+	set, largest := srcset(fallback)
+	fmt.Fprintf(&sb, "  <img src=\"%s\" srcset=\"%s\" sizes=\"%s\" width=\"%d\" height=\"%d\" alt=\"%s\" loading=\"lazy\" decoding=\"async\">\n",
+		largest.File, set, sizes, largest.Width, largest.Height, html.EscapeString(opts.Alt))
+	sb.WriteString("</picture>\n")
+
+	return sb.String()
+}
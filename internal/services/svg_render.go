@@ -0,0 +1,66 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// isSVG reports whether data looks like an SVG document. SVG is text, so it
+// has no magic bytes for DetectImageFormat to find.
+func isSVG(data []byte) bool {
+	head := data[:min(len(data), 4096)]
+	head = bytes.TrimPrefix(head, []byte("\xEF\xBB\xBF"))
+	head = bytes.TrimSpace(head)
+	if !bytes.HasPrefix(head, []byte("<")) {
+		return false
+	}
+	return bytes.Contains(head, []byte("<svg"))
+}
+
+// RasterizeSVG sanitizes an SVG and renders it with the pure Go oksvg
+// renderer. A zero width or height is derived from the other side using the
+// viewBox aspect ratio; when both are zero the viewBox size is used.
+func RasterizeSVG(data []byte, width, height int) (*image.NRGBA, error) {
+	data, _, err := SanitizeSVG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SVG: %w", err)
+	}
+
+	vw, vh := icon.ViewBox.W, icon.ViewBox.H
+	if vw <= 0 || vh <= 0 {
+		return nil, fmt.Errorf("SVG has no size; add a viewBox or width and height")
+	}
+
+	switch {
+	case width <= 0 && height <= 0:
+		width, height = int(math.Ceil(vw)), int(math.Ceil(vh))
+	case width <= 0:
+		width = max(1, int(math.Round(float64(height)*vw/vh)))
+	case height <= 0:
+		height = max(1, int(math.Round(float64(width)*vh/vw)))
+	}
+	if err := checkImageDimensions(width, height); err != nil {
+		return nil, err
+	}
+	if limit := DefaultImageLimits.MaxMegapixels; float64(width)*float64(height) > limit*1e6 {
+		return nil, fmt.Errorf("%w: %dx%d is more than %g megapixels", ErrImageTooLarge, width, height, limit)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	icon.SetTarget(0, 0, float64(width), float64(height))
+
+	scanner := rasterx.NewScannerGV(width, height, img, img.Bounds())
+	icon.Draw(rasterx.NewDasher(width, height, scanner), 1)
+
+	return img, nil
+}
@@ -0,0 +1,539 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxSVGDepth rejects absurdly nested documents before they are walked
+// recursively
+const maxSVGDepth = 256
+
+// MaxSVGPrecision is the most decimal places OptimizeSVG keeps
+const MaxSVGPrecision = 8
+
+// svgNode is an element, or text when name is empty. Names keep their
+// prefix ("xlink:href") as written, since documents are re-serialized
+// rather than interpreted.
+type svgNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*svgNode
+	text     string
+}
+
+func (n *svgNode) attr(name string) (string, bool) {
+	for _, a := range n.attrs {
+		if attrName(a.Name) == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func attrName(n xml.Name) string {
+	if n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+// parseSVG reads an SVG document into a tree of its root element. Comments,
+// processing instructions and the DOCTYPE are dropped, which also means
+// custom entities are never expanded.
+func parseSVG(data []byte) (*svgNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.Entity = xml.HTMLEntity
+
+	var root *svgNode
+	var stack []*svgNode
+	for {
+		tok, err := d.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SVG: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) >= maxSVGDepth {
+				return nil, fmt.Errorf("SVG is nested more than %d levels deep", maxSVGDepth)
+			}
+			n := &svgNode{name: attrName(t.Name), attrs: slices.Clone(t.Attr)}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("SVG has more than one root element")
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("failed to parse SVG: unexpected </%s>", attrName(t.Name))
+			}
+			stack = stack[:len(stack)-1]
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &svgNode{text: string(t)})
+			}
+		}
+	}
+
+	if root == nil || root.name != "svg" && !strings.HasSuffix(root.name, ":svg") {
+		return nil, fmt.Errorf("not an SVG document")
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("failed to parse SVG: unclosed <%s>", stack[len(stack)-1].name)
+	}
+	return root, nil
+}
+
+// writeSVG serializes the tree without any added whitespace
+func writeSVG(w *bytes.Buffer, n *svgNode) {
+	if n.name == "" {
+		w.WriteString(svgTextEscaper.Replace(n.text))
+		return
+	}
+
+	w.WriteString("<" + n.name)
+	for _, a := range n.attrs {
+		w.WriteString(" " + attrName(a.Name) + `="`)
+		w.WriteString(svgAttrEscaper.Replace(a.Value))
+		w.WriteString(`"`)
+	}
+	if len(n.children) == 0 {
+		w.WriteString("/>")
+		return
+	}
+
+	w.WriteString(">")
+	for _, c := range n.children {
+		writeSVG(w, c)
+	}
+	w.WriteString("</" + n.name + ">")
+}
+
+var svgTextEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `>`, "&gt;")
+
+var svgAttrEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `"`, "&quot;", "\n", "&#10;", "\t", "&#9;", "\r", "&#13;")
+
+// svgUnsafeElements can run script or embed other documents
+var svgUnsafeElements = []string{"script", "foreignObject", "iframe", "embed", "object", "audio", "video", "handler", "listener"}
+
+var (
+	cssImportPattern = regexp.MustCompile(`(?i)@import[^;]*;?`)
+	cssURLPattern    = regexp.MustCompile(`(?i)url\(\s*['"]?([^)'"]*)['"]?\s*\)`)
+	cssScriptPattern = regexp.MustCompile(`(?i)(expression\s*\(|javascript:|-moz-binding)`)
+)
+
+// SVGSanitizeReport lists what sanitizing removed, e.g. "<script>" or
+// "onload attribute"
+type SVGSanitizeReport struct {
+	Removed []string `json:"removed"`
+}
+
+func (r *SVGSanitizeReport) add(what string) {
+	if !slices.Contains(r.Removed, what) {
+		r.Removed = append(r.Removed, what)
+	}
+}
+
+// SanitizeSVG removes scripts, event handlers, embedded documents and
+// references to anything outside the file, so the result is safe to render
+// or serve. Fragment links (#id) and embedded raster images are kept.
+func SanitizeSVG(data []byte) ([]byte, *SVGSanitizeReport, error) {
+	root, err := parseSVG(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &SVGSanitizeReport{Removed: []string{}}
+	sanitizeSVGNode(root, report)
+
+	var buf bytes.Buffer
+	writeSVG(&buf, root)
+	return buf.Bytes(), report, nil
+}
+
+func sanitizeSVGNode(n *svgNode, report *SVGSanitizeReport) {
+	n.attrs = slices.DeleteFunc(n.attrs, func(a xml.Attr) bool {
+		name := strings.ToLower(a.Name.Local)
+		switch {
+		case strings.HasPrefix(name, "on"):
+			report.add(name + " attribute")
+			return true
+		case name == "href" && !safeSVGReference(a.Value):
+			report.add("external reference")
+			return true
+		}
+		return false
+	})
+	for i, a := range n.attrs {
+		// presentation attributes such as fill="url(...)" can point outside
+		// the file as well as style
+		if strings.EqualFold(a.Name.Local, "style") || strings.Contains(strings.ToLower(a.Value), "url(") {
+			n.attrs[i].Value = sanitizeSVGStyle(a.Value, report)
+		}
+	}
+
+	n.children = slices.DeleteFunc(n.children, func(c *svgNode) bool {
+		if c.name == "" {
+			return false
+		}
+		local := c.name[strings.LastIndex(c.name, ":")+1:]
+		if slices.ContainsFunc(svgUnsafeElements, func(e string) bool { return strings.EqualFold(e, local) }) {
+			report.add("<" + local + ">")
+			return true
+		}
+
+		// animations can set href or event handlers after load
+		if local == "animate" || local == "set" || local == "animateTransform" || local == "animateMotion" {
+			target, _ := c.attr("attributeName")
+			target = strings.ToLower(target)
+			if strings.HasSuffix(target, "href") || strings.HasPrefix(target, "on") {
+				report.add("<" + local + "> of " + target)
+				return true
+			}
+		}
+		return false
+	})
+
+	for _, c := range n.children {
+		if c.name == "" {
+			if n.name == "style" {
+				c.text = sanitizeSVGStyle(c.text, report)
+			}
+			continue
+		}
+		sanitizeSVGNode(c, report)
+	}
+}
+
+// safeSVGReference allows links within the document and inline raster
+// images; anything else could fetch or navigate to another resource
+func safeSVGReference(ref string) bool {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if strings.HasPrefix(ref, "#") {
+		return true
+	}
+	for _, prefix := range []string{"data:image/png", "data:image/jpeg", "data:image/gif", "data:image/webp"} {
+		if strings.HasPrefix(ref, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sanitizeSVGStyle(css string, report *SVGSanitizeReport) string {
+	if cssImportPattern.MatchString(css) {
+		report.add("CSS @import")
+		css = cssImportPattern.ReplaceAllString(css, "")
+	}
+	css = cssURLPattern.ReplaceAllStringFunc(css, func(m string) string {
+		ref := cssURLPattern.FindStringSubmatch(m)[1]
+		if safeSVGReference(ref) {
+			return m
+		}
+		report.add("external reference")
+		return "none"
+	})
+	if cssScriptPattern.MatchString(css) {
+		report.add("CSS script")
+		css = cssScriptPattern.ReplaceAllString(css, "")
+	}
+	return css
+}
+
+type SVGOptimizeOptions struct {
+	// Precision is the number of decimal places coordinates are rounded
+	// to; 0 rounds to whole numbers
+	Precision int
+
+	// KeepTitle keeps <title> and <desc>, which screen readers announce
+	KeepTitle bool
+}
+
+// svgEditorNamespaces are added by drawing programs and mean nothing to a
+// browser
+var svgEditorNamespaces = []string{
+	"http://www.inkscape.org/namespaces/inkscape",
+	"http://sodipodi.sourceforge.net/DTD/sodipodi-0.dtd",
+	"http://www.bohemiancoding.com/sketch/ns",
+	"http://www.serif.com/",
+	"http://www.figma.com/figma/ns",
+	"http://ns.adobe.com/AdobeIllustrator/10.0/",
+	"http://ns.adobe.com/AdobeSVGViewerExtensions/3.0/",
+	"http://ns.adobe.com/Extensibility/1.0/",
+	"http://ns.adobe.com/Flows/1.0/",
+	"http://ns.adobe.com/ImageReplacement/1.0/",
+	"http://ns.adobe.com/SaveForWeb/1.0/",
+	"http://ns.adobe.com/Variables/1.0/",
+	"http://ns.adobe.com/GenericCustomNamespace/1.0/",
+	"http://ns.adobe.com/XPath/1.0/",
+	"http://purl.org/dc/elements/1.1/",
+	"http://creativecommons.org/ns#",
+	"http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+}
+
+// svgNumericAttrs hold only numbers and separators (or transform function
+// names), so every number in them can be rounded
+var svgNumericAttrs = []string{
+	"d", "points", "viewBox", "transform", "gradientTransform", "patternTransform",
+	"x", "y", "x1", "y1", "x2", "y2", "cx", "cy", "r", "rx", "ry", "fx", "fy",
+	"width", "height", "stroke-width", "stroke-dashoffset", "stroke-dasharray", "font-size",
+}
+
+var svgNumberPattern = regexp.MustCompile(`-?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// svgTextElements keep whitespace-only text, which may be a visible space
+var svgTextElements = []string{"text", "tspan", "textPath"}
+
+// OptimizeSVG sanitizes and minifies an SVG: comments, metadata and editor
+// namespaces are removed, attribute-less groups are unwrapped, coordinates
+// are rounded and formatting whitespace is dropped
+func OptimizeSVG(data []byte, opts SVGOptimizeOptions) ([]byte, *SVGSanitizeReport, error) {
+	if opts.Precision < 0 || opts.Precision > MaxSVGPrecision {
+		return nil, nil, fmt.Errorf("precision must be between 0 and %d", MaxSVGPrecision)
+	}
+
+	root, err := parseSVG(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &SVGSanitizeReport{Removed: []string{}}
+	sanitizeSVGNode(root, report)
+
+	// prefixes bound to editor namespaces anywhere in the document
+	editorPrefixes := map[string]bool{}
+	var collect func(n *svgNode)
+	collect = func(n *svgNode) {
+		for _, a := range n.attrs {
+			if a.Name.Space == "xmlns" && slices.Contains(svgEditorNamespaces, a.Value) {
+				editorPrefixes[a.Name.Local] = true
+			}
+		}
+		for _, c := range n.children {
+			collect(c)
+		}
+	}
+	collect(root)
+
+	optimizeSVGNode(root, opts, editorPrefixes)
+
+	// drop namespace declarations nothing uses any more
+	used := map[string]bool{}
+	var mark func(n *svgNode)
+	mark = func(n *svgNode) {
+		if i := strings.Index(n.name, ":"); i > 0 {
+			used[n.name[:i]] = true
+		}
+		for _, a := range n.attrs {
+			if a.Name.Space != "" && a.Name.Space != "xmlns" {
+				used[a.Name.Space] = true
+			}
+		}
+		for _, c := range n.children {
+			mark(c)
+		}
+	}
+	mark(root)
+
+	var prune func(n *svgNode)
+	prune = func(n *svgNode) {
+		n.attrs = slices.DeleteFunc(n.attrs, func(a xml.Attr) bool {
+			return a.Name.Space == "xmlns" && a.Name.Local != "xml" && !used[a.Name.Local]
+		})
+		for _, c := range n.children {
+			prune(c)
+		}
+	}
+	prune(root)
+
+	var buf bytes.Buffer
+	writeSVG(&buf, root)
+	return buf.Bytes(), report, nil
+}
+
+func optimizeSVGNode(n *svgNode, opts SVGOptimizeOptions, editorPrefixes map[string]bool) {
+	n.attrs = slices.DeleteFunc(n.attrs, func(a xml.Attr) bool {
+		return editorPrefixes[a.Name.Space] || a.Name.Space == "xmlns" && editorPrefixes[a.Name.Local]
+	})
+	for i, a := range n.attrs {
+		if a.Name.Space == "" && slices.Contains(svgNumericAttrs, a.Name.Local) {
+			n.attrs[i].Value = roundSVGNumbers(strings.TrimSpace(a.Value), opts.Precision)
+		}
+	}
+
+	keepSpace := slices.Contains(svgTextElements, n.name)
+
+	var children []*svgNode
+	for _, c := range n.children {
+		if c.name == "" {
+			switch {
+			case keepSpace:
+			case n.name == "style":
+				c.text = strings.Join(strings.Fields(c.text), " ")
+			case strings.TrimSpace(c.text) == "":
+				continue
+			}
+			children = append(children, c)
+			continue
+		}
+
+		if i := strings.Index(c.name, ":"); i > 0 && editorPrefixes[c.name[:i]] {
+			continue
+		}
+		switch c.name {
+		case "metadata":
+			continue
+		case "title", "desc":
+			if !opts.KeepTitle {
+				continue
+			}
+		}
+
+		optimizeSVGNode(c, opts, editorPrefixes)
+
+		switch {
+		case (c.name == "g" || c.name == "defs") && len(c.children) == 0:
+			// nothing to draw or reference
+			continue
+		case c.name == "g" && len(c.attrs) == 0:
+			// a group without attributes changes nothing about its children
+			children = append(children, c.children...)
+			continue
+		}
+		children = append(children, c)
+	}
+	n.children = children
+}
+
+// roundSVGNumbers rounds every number in s, dropping trailing zeros and the
+// leading zero of fractions
+func roundSVGNumbers(s string, precision int) string {
+	return svgNumberPattern.ReplaceAllStringFunc(s, func(m string) string {
+		// "011" is three arc flags written without separators, not a number
+		if d := strings.TrimPrefix(m, "-"); len(d) > 1 && d[0] == '0' && d[1] != '.' {
+			return m
+		}
+
+		v, err := strconv.ParseFloat(m, 64)
+		if err != nil {
+			return m
+		}
+
+		scale := math.Pow(10, float64(precision))
+		v = math.Round(v*scale) / scale
+		if v == 0 {
+			// avoid "-0"
+			return "0"
+		}
+
+		out := strconv.FormatFloat(v, 'f', -1, 64)
+		if strings.HasPrefix(out, "0.") {
+			out = out[1:]
+		} else if strings.HasPrefix(out, "-0.") {
+			out = "-" + out[2:]
+		}
+		return out
+	})
+}
+
+// MaxSVGRasterSizes caps how many sizes one request may render
+const MaxSVGRasterSizes = 12
+
+type SVGRasterizeOptions struct {
+	// Format is "png" (the default) or "webp"
+	Format string
+
+	// Widths to render at, keeping the aspect ratio; empty renders once at
+	// the SVG's own size
+	Widths []int
+
+	// Quality for WebP
+	Quality int
+
+	// BaseName prefixes every file name, e.g. "logo" gives logo-64.png
+	BaseName string
+}
+
+// SVGRaster is one rendered size
+type SVGRaster struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int    `json:"size"`
+	Data   []byte `json:"-"`
+}
+
+// RasterizeSVGSizes renders a sanitized SVG at each requested width
+func RasterizeSVGSizes(data []byte, opts SVGRasterizeOptions) ([]SVGRaster, error) {
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "webp" {
+		return nil, fmt.Errorf("SVGs can be rasterized to PNG or WebP only")
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		opts.Quality = 90
+	}
+	if len(opts.Widths) > MaxSVGRasterSizes {
+		return nil, fmt.Errorf("at most %d sizes can be rendered at once", MaxSVGRasterSizes)
+	}
+
+	widths := slices.Clone(opts.Widths)
+	if len(widths) == 0 {
+		widths = []int{0}
+	}
+	slices.Sort(widths)
+	widths = slices.Compact(widths)
+
+	baseName := responsiveBaseName(opts.BaseName)
+	_, ext := ImageContentType(format)
+
+	var rasters []SVGRaster
+	for _, w := range widths {
+		if w < 0 {
+			return nil, fmt.Errorf("width cannot be negative")
+		}
+
+		img, err := RasterizeSVG(data, w, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := encodeImage(&buf, img, format, opts.Quality); err != nil {
+			return nil, fmt.Errorf("failed to encode %dpx image: %w", img.Bounds().Dx(), err)
+		}
+
+		b := img.Bounds()
+		rasters = append(rasters, SVGRaster{
+			Name:   fmt.Sprintf("%s-%d.%s", baseName, b.Dx(), ext),
+			Width:  b.Dx(),
+			Height: b.Dy(),
+			Size:   buf.Len(),
+			Data:   buf.Bytes(),
+		})
+	}
+
+	return rasters, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeSVG(t *testing.T) {
+	const open = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">`
+
+	tests := []struct {
+		name    string
+		input   string
+		removed string   // expected in the report, if set
+		absent  []string // must not appear in the output
+		present []string // must survive sanitizing
+	}{
+		{
+			name:    "script element",
+			input:   open + `<script>alert(1)</script><rect width="1" height="1"/></svg>`,
+			removed: "<script>",
+			absent:  []string{"script", "alert"},
+			present: []string{`<rect width="1" height="1"/>`},
+		},
+		{
+			name:    "prefixed script element",
+			input:   `<svg:svg xmlns:svg="http://www.w3.org/2000/svg"><svg:script>alert(1)</svg:script></svg:svg>`,
+			removed: "<script>",
+			absent:  []string{"alert"},
+		},
+		{
+			name:    "event handler",
+			input:   open + `<rect onload="alert(1)" width="1"/></svg>`,
+			removed: "onload attribute",
+			absent:  []string{"onload", "alert"},
+			present: []string{`width="1"`},
+		},
+		{
+			name:    "upper case event handler",
+			input:   open + `<rect ONCLICK="alert(1)"/></svg>`,
+			removed: "onclick attribute",
+			absent:  []string{"alert"},
+		},
+		{
+			name:    "foreignObject",
+			input:   open + `<foreignObject><div xmlns="http://www.w3.org/1999/xhtml">hi</div></foreignObject></svg>`,
+			removed: "<foreignObject>",
+			absent:  []string{"foreignObject", "div"},
+		},
+		{
+			name:    "javascript link",
+			input:   open + `<a xlink:href="javascript:alert(1)"><rect/></a></svg>`,
+			removed: "external reference",
+			absent:  []string{"javascript"},
+			present: []string{"<rect/>"},
+		},
+		{
+			name:    "external image",
+			input:   open + `<image href="https://example.com/x.png"/></svg>`,
+			removed: "external reference",
+			absent:  []string{"example.com"},
+		},
+		{
+			name:    "SVG data URI",
+			input:   open + `<image href="data:image/svg+xml;base64,PHN2Zz4="/></svg>`,
+			removed: "external reference",
+			absent:  []string{"data:image/svg"},
+		},
+		{
+			name:    "fragment and raster data URI are kept",
+			input:   open + `<use href="#a"/><image href="data:image/png;base64,AAAA"/></svg>`,
+			present: []string{`href="#a"`, `href="data:image/png;base64,AAAA"`},
+		},
+		{
+			name:    "external url in style attribute",
+			input:   open + `<rect style="fill:url(https://example.com/p.svg#g)"/></svg>`,
+			removed: "external reference",
+			absent:  []string{"example.com"},
+			present: []string{"fill:none"},
+		},
+		{
+			name:    "external url in presentation attribute",
+			input:   open + `<rect fill="url('https://example.com/p.svg#g')"/></svg>`,
+			removed: "external reference",
+			absent:  []string{"example.com"},
+		},
+		{
+			name:    "local url in presentation attribute is kept",
+			input:   open + `<rect fill="url(#grad)"/></svg>`,
+			present: []string{`fill="url(#grad)"`},
+		},
+		{
+			name:    "style element import",
+			input:   open + `<style>@import url(https://example.com/a.css); rect { fill: red }</style></svg>`,
+			removed: "CSS @import",
+			absent:  []string{"@import", "example.com"},
+			present: []string{"fill: red"},
+		},
+		{
+			name:    "script in CSS",
+			input:   open + `<rect style="background:javascript:alert(1)"/></svg>`,
+			removed: "CSS script",
+			absent:  []string{"javascript:"},
+		},
+		{
+			name:    "animation setting href",
+			input:   open + `<a><set attributeName="href" to="javascript:alert(1)"/></a></svg>`,
+			removed: "<set> of href",
+			absent:  []string{"javascript"},
+		},
+		{
+			name:    "animation setting an event handler",
+			input:   open + `<rect><animate attributeName="onclick" values="alert(1)"/></rect></svg>`,
+			removed: "<animate> of onclick",
+			absent:  []string{"alert"},
+		},
+		{
+			name:    "harmless animation is kept",
+			input:   open + `<rect><animate attributeName="opacity" values="0;1"/></rect></svg>`,
+			present: []string{`attributeName="opacity"`},
+		},
+		{
+			name:    "text is escaped on output",
+			input:   open + `<text>a &lt;script&gt; b</text></svg>`,
+			absent:  []string{"<script>"},
+			present: []string{"&lt;script&gt;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, report, err := SanitizeSVG([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("SanitizeSVG: %v", err)
+			}
+
+			for _, s := range tt.absent {
+				if strings.Contains(string(out), s) {
+					t.Errorf("output contains %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.present {
+				if !strings.Contains(string(out), s) {
+					t.Errorf("output is missing %q:\n%s", s, out)
+				}
+			}
+			if tt.removed != "" && !slices.Contains(report.Removed, tt.removed) {
+				t.Errorf("report %q does not list %q", report.Removed, tt.removed)
+			}
+			if tt.removed == "" && len(report.Removed) > 0 {
+				t.Errorf("nothing should be removed, report lists %q", report.Removed)
+			}
+		})
+	}
+}
+
+func TestSanitizeSVGRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not SVG", `<html><body/></html>`},
+		{"two roots", `<svg xmlns="http://www.w3.org/2000/svg"/><svg xmlns="http://www.w3.org/2000/svg"/>`},
+		{"unclosed", `<svg xmlns="http://www.w3.org/2000/svg"><g>`},
+		{"custom entity", `<!DOCTYPE svg [<!ENTITY x "SECRET">]><svg xmlns="http://www.w3.org/2000/svg"><text>&x;</text></svg>`},
+		{"too deep", `<svg xmlns="http://www.w3.org/2000/svg">` + strings.Repeat("<g>", maxSVGDepth) + strings.Repeat("</g>", maxSVGDepth) + `</svg>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := SanitizeSVG([]byte(tt.input))
+			if err == nil {
+				t.Errorf("expected an error, got %s", out)
+			}
+		})
+	}
+}
+
+func TestRoundSVGNumbers(t *testing.T) {
+	tests := []struct {
+		input     string
+		precision int
+		want      string
+	}{
+		{"M10.123456 20.5", 2, "M10.12 20.5"},
+		{"0.5 -0.25", 3, ".5 -.25"},
+		{"1.00 2.10", 2, "1 2.1"},
+		{"-0.0001", 2, "0"},
+		{"1e3 2.5e-1", 1, "1000 .3"},
+		{"12.6", 0, "13"},
+		// arc flags written without separators
+		{"a10 10 0 011 20 20", 1, "a10 10 0 011 20 20"},
+		{"translate(3.14159,-2.71828)", 2, "translate(3.14,-2.72)"},
+	}
+
+	for _, tt := range tests {
+		if got := roundSVGNumbers(tt.input, tt.precision); got != tt.want {
+			t.Errorf("roundSVGNumbers(%q, %d) = %q, want %q", tt.input, tt.precision, got, tt.want)
+		}
+	}
+}
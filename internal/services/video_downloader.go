@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
@@ -24,29 +25,96 @@ type VideoDownloadOptions struct {
 	SubtitlesLang      string
 	CookiesPath        string
 	CookiesFromBrowser string
+
+	// VideoFormatID and AudioFormatID pick exact formats from
+	// VideoInfo.Formats and take precedence over Quality. Either may be
+	// empty to download a muxed or audio-only format on its own.
+	VideoFormatID string
+	AudioFormatID string
+
+	// Codec is "h264" to prefer H.264/AAC for compatibility, "no-av1" to
+	// avoid AV1, or empty for the best quality regardless of codec
+	Codec string
+
+	// AudioFormat is the file type extracted when Quality is "audio": mp3
+	// (the default), m4a, opus, flac or wav
+	AudioFormat string
+
+	// AudioBitrate in kbps for lossy formats; 0 uses the best VBR quality
+	AudioBitrate int
+
+	// EmbedThumbnail adds the video thumbnail as cover art and EmbedMetadata
+	// writes title, artist and album tags to extracted audio
+	EmbedThumbnail bool
+	EmbedMetadata  bool
+
+	// ClipStart and ClipEnd, in seconds, download only that part of the
+	// video. A ClipEnd of 0 downloads the whole video.
+	ClipStart float64
+	ClipEnd   float64
+
+	// ForceKeyframes re-encodes around the cuts so the clip starts and ends
+	// exactly on the requested times instead of the nearest keyframes
+	ForceKeyframes bool
 }
 
 type VideoInfo struct {
-	Title       string   `json:"title"`
-	Duration    int      `json:"duration"`
-	Description string   `json:"description"`
-	Uploader    string   `json:"uploader"`
-	Thumbnail   string   `json:"thumbnail"`
-	Formats     []Format `json:"formats"`
-	IsYouTube   bool     `json:"is_youtube"`
-	FileSize    int64    `json:"filesize"`
+	Title       string    `json:"title"`
+	Duration    int       `json:"duration"`
+	Description string    `json:"description"`
+	Uploader    string    `json:"uploader"`
+	Thumbnail   string    `json:"thumbnail"`
+	Formats     []Format  `json:"formats"`
+	IsYouTube   bool      `json:"is_youtube"`
+	FileSize    int64     `json:"filesize"`
+	Chapters    []Chapter `json:"chapters"`
+
+	// SubtitleLanguages lists the uploaded and automatic subtitle tracks
+	SubtitleLanguages []SubtitleLanguage `json:"subtitle_languages"`
+}
+
+type Chapter struct {
+	Title     string  `json:"title"`
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
 }
 
 type Format struct {
-	FormatID   string `json:"format_id"`
-	Extension  string `json:"ext"`
-	Resolution string `json:"resolution"`
-	FileSize   int64  `json:"filesize"`
-	FPS        int    `json:"fps"`
-	VideoCodec string `json:"vcodec"`
-	AudioCodec string `json:"acodec"`
+	FormatID       string  `json:"format_id"`
+	Extension      string  `json:"ext"`
+	Resolution     string  `json:"resolution"`
+	Height         int     `json:"height"`
+	FormatNote     string  `json:"format_note"`
+	FileSize       int64   `json:"filesize"`
+	FileSizeApprox int64   `json:"filesize_approx"`
+	Bitrate        float64 `json:"tbr"`
+	FPS            float64 `json:"fps"`
+	VideoCodec     string  `json:"vcodec"`
+	AudioCodec     string  `json:"acodec"`
+
+	// Kind is "muxed" for formats with both video and audio, "video" or
+	// "audio"
+	Kind string `json:"kind"`
+}
+
+// ExtractAudioFormats are the formats audio can be extracted to
+var ExtractAudioFormats = []string{"mp3", "m4a", "opus", "flac", "wav"}
+
+// audioContentTypes maps the audio formats that can be extracted, and the
+// containers sites serve audio-only formats in, to their MIME types
+var audioContentTypes = map[string]string{
+	"mp3":  "audio/mpeg",
+	"m4a":  "audio/mp4",
+	"opus": "audio/ogg",
+	"flac": "audio/flac",
+	"wav":  "audio/wav",
+	"webm": "audio/webm",
 }
 
+// formatIDPattern matches yt-dlp format IDs such as "137", "hls-1080p" or
+// "dash-audio=128000"
+var formatIDPattern = regexp.MustCompile(`^[\w.=:-]+$`)
+
 func GetVideoInfo(ctx context.Context, videoURL string, cookiesFromBrowser string, cookiesPath string) (*VideoInfo, error) {
 	ytDlpPath, err := exec.LookPath("yt-dlp")
 	if err != nil {
@@ -111,6 +179,30 @@ func GetVideoInfo(ctx context.Context, videoURL string, cookiesFromBrowser strin
 	}
 
 	info.IsYouTube = isYouTube
+	info.SubtitleLanguages = subtitleLanguages(stdout.Bytes())
+
+	// label each format and drop the ones that are neither video nor audio,
+	// such as storyboard images
+	formats := info.Formats[:0]
+	for _, f := range info.Formats {
+		hasVideo := f.VideoCodec != "" && f.VideoCodec != "none"
+		hasAudio := f.AudioCodec != "" && f.AudioCodec != "none"
+		switch {
+		case hasVideo && hasAudio:
+			f.Kind = "muxed"
+		case hasVideo:
+			f.Kind = "video"
+		case hasAudio:
+			f.Kind = "audio"
+		default:
+			continue
+		}
+		if f.FileSize == 0 {
+			f.FileSize = f.FileSizeApprox
+		}
+		formats = append(formats, f)
+	}
+	info.Formats = formats
 
 	return &info, nil
 }
@@ -125,6 +217,12 @@ func DownloadVideo(ctx context.Context, opts VideoDownloadOptions) (string, erro
 		return "", fmt.Errorf("invalid URL: %s", opts.URL)
 	}
 
+	if opts.Quality == "audio" {
+		if err := validateAudioOptions(&opts); err != nil {
+			return "", err
+		}
+	}
+
 	tmpDir, err := os.MkdirTemp("", "video-download-*")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp directory: %w", err)
@@ -209,14 +307,30 @@ func buildDownloadArgs(opts VideoDownloadOptions, outputTemplate string) []strin
 	}
 
 	if opts.Quality == "audio" {
+		audioQuality := "0"
+		if opts.AudioBitrate > 0 {
+			audioQuality = fmt.Sprintf("%dK", opts.AudioBitrate)
+		}
+
 		args = append(args,
 			"-f", "bestaudio",
 			"--extract-audio",
-			"--audio-format", "mp3",
-			"--audio-quality", "0",
+			"--audio-format", opts.AudioFormat,
+			"--audio-quality", audioQuality,
 		)
+
+		if opts.EmbedMetadata {
+			args = append(args, "--embed-metadata")
+		}
+		// WAV has no standard place for cover art
+		if opts.EmbedThumbnail && opts.AudioFormat != "wav" {
+			args = append(args, "--embed-thumbnail")
+		}
 	} else {
-		formatSelector := buildFormatSelector(opts.Quality)
+		formatSelector := buildFormatSelector(opts.Quality, opts.Codec)
+		if ids := explicitFormatSelector(opts); ids != "" {
+			formatSelector = ids
+		}
 		args = append(args, "-f", formatSelector)
 
 		args = append(args, "--merge-output-format", opts.Format)
@@ -230,7 +344,14 @@ func buildDownloadArgs(opts VideoDownloadOptions, outputTemplate string) []strin
 		)
 	}
 
-	if opts.MaxDuration > 0 {
+	if opts.ClipEnd > 0 {
+		args = append(args, "--download-sections",
+			fmt.Sprintf("*%s-%s", formatSeconds(opts.ClipStart), formatSeconds(opts.ClipEnd)))
+		if opts.ForceKeyframes {
+			args = append(args, "--force-keyframes-at-cuts")
+		}
+	} else if opts.MaxDuration > 0 {
+		// clips are checked against MaxDuration by ValidateClip instead
 		args = append(args, "--match-filter",
 			fmt.Sprintf("duration <= %d", opts.MaxDuration))
 	}
@@ -240,34 +361,112 @@ func buildDownloadArgs(opts VideoDownloadOptions, outputTemplate string) []strin
 	return args
 }
 
-func buildFormatSelector(quality string) string {
+// validateAudioOptions applies the audio defaults and rejects formats and
+// bitrates yt-dlp would not understand
+func validateAudioOptions(opts *VideoDownloadOptions) error {
+	if opts.AudioFormat == "" {
+		opts.AudioFormat = "mp3"
+	}
+	if !slices.Contains(ExtractAudioFormats, opts.AudioFormat) {
+		return fmt.Errorf("unsupported audio format: %s", opts.AudioFormat)
+	}
+
+	// lossless formats have no bitrate to choose
+	if opts.AudioFormat == "flac" || opts.AudioFormat == "wav" {
+		opts.AudioBitrate = 0
+	}
+	if opts.AudioBitrate != 0 && (opts.AudioBitrate < 32 || opts.AudioBitrate > 320) {
+		return fmt.Errorf("audio bitrate must be between 32 and 320 kbps")
+	}
+
+	return nil
+}
+
+// AudioContentType returns the MIME type for an audio file extension, with
+// or without the leading dot
+func AudioContentType(ext string) string {
+	if contentType, ok := audioContentTypes[strings.TrimPrefix(strings.ToLower(ext), ".")]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// ValidateClip checks that a clip from start to end seconds lies within the
+// video and is no longer than maxDuration seconds
+func ValidateClip(info *VideoInfo, start, end float64, maxDuration int) error {
+	if start < 0 {
+		return fmt.Errorf("clip start cannot be negative")
+	}
+	if end <= start {
+		return fmt.Errorf("clip end must be after the start")
+	}
+	// live streams and some sites report no duration
+	if info.Duration > 0 && end > float64(info.Duration) {
+		return fmt.Errorf("clip end (%s) is past the end of the video (%ds)", formatSeconds(end), info.Duration)
+	}
+	if maxDuration > 0 && end-start > float64(maxDuration) {
+		return fmt.Errorf("clips are limited to %d seconds", maxDuration)
+	}
+	return nil
+}
+
+func buildFormatSelector(quality string, codec string) string {
+	var limit string
 	switch quality {
 	case "2160p", "4k":
 		// 4K video with best audio
-		return "bestvideo[height<=2160]+bestaudio/best[height<=2160]"
+		limit = "[height<=2160]"
 	case "1440p", "2k":
 		// 1440p video with best audio
-		return "bestvideo[height<=1440]+bestaudio/best[height<=1440]"
+		limit = "[height<=1440]"
 	case "1080p":
 		// Full HD video with best audio
-		return "bestvideo[height<=1080]+bestaudio/best[height<=1080]"
+		limit = "[height<=1080]"
 	case "720p":
 		// HD video with best audio
-		return "bestvideo[height<=720]+bestaudio/best[height<=720]"
+		limit = "[height<=720]"
 	case "480p":
 		// SD video with best audio
-		return "bestvideo[height<=480]+bestaudio/best[height<=480]"
+		limit = "[height<=480]"
 	case "360p":
 		// Low quality for slow connections
-		return "bestvideo[height<=360]+bestaudio/best[height<=360]"
-	case "best":
-		fallthrough
+		limit = "[height<=360]"
+	default:
+		// "best": the best available quality
+	}
+
+	best := fmt.Sprintf("bestvideo%s+bestaudio/best%s", limit, limit)
+
+	// the preferred codecs are tried first, falling back to any codec when
+	// the site does not offer them
+	switch codec {
+	case "h264":
+		return fmt.Sprintf("bestvideo%s[vcodec^=avc1]+bestaudio[acodec^=mp4a]/best%s[vcodec^=avc1]/", limit, limit) + best
+	case "no-av1":
+		return fmt.Sprintf("bestvideo%s[vcodec!^=av01]+bestaudio/", limit) + best
 	default:
-		// Best available quality
-		return "bestvideo+bestaudio/best"
+		return best
 	}
 }
 
+// explicitFormatSelector joins the chosen format IDs into a selector such as
+// "137+140", or returns "" when none were chosen
+func explicitFormatSelector(opts VideoDownloadOptions) string {
+	var ids []string
+	for _, id := range []string{opts.VideoFormatID, opts.AudioFormatID} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return strings.Join(ids, "+")
+}
+
+// ValidFormatID reports whether id looks like a yt-dlp format ID rather than
+// a selector expression
+func ValidFormatID(id string) bool {
+	return formatIDPattern.MatchString(id)
+}
+
 func isValidURL(str string) bool {
 	u, err := url.Parse(str)
 	return err == nil && u.Scheme != "" && u.Host != ""
@@ -279,6 +478,7 @@ func IsYouTubeURL(urlStr string) bool {
 		`^https?://(?:www\.)?youtube\.com/embed/`,
 		`^https?://youtu\.be/`,
 		`^https?://(?:www\.)?youtube\.com/v/`,
+		`^https?://(?:www\.|m\.)?youtube\.com/(?:playlist\?|shorts/|@|channel/|c/|user/)`,
 	}
 
 	for _, pattern := range patterns {
@@ -335,7 +535,7 @@ func StreamingDownload(ctx context.Context, opts VideoDownloadOptions, w io.Writ
 		"-o", "-", // Output to stdout
 	}
 
-	formatSelector := buildFormatSelector(opts.Quality)
+	formatSelector := buildFormatSelector(opts.Quality, opts.Codec)
 	args = append(args, "-f", formatSelector)
 
 	args = append(args, opts.URL)
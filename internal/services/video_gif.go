@@ -0,0 +1,261 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrFFmpegUnavailable is returned when a video tool runs without ffmpeg
+var ErrFFmpegUnavailable = errors.New("video conversion requires ffmpeg (install with: apt-get install ffmpeg)")
+
+const (
+	// MaxGIFDuration is the longest clip, in seconds, that can be converted
+	MaxGIFDuration = 30
+
+	// MaxGIFWidth is the widest animation that can be produced
+	MaxGIFWidth = 1280
+
+	// MaxGIFFPS is the highest frame rate; browsers clamp GIF frame delays
+	// below 20ms anyway
+	MaxGIFFPS = 30
+
+	// MaxGIFOutputSize is the largest animation returned
+	MaxGIFOutputSize = 50 << 20
+)
+
+type VideoToGIFOptions struct {
+	// Start and End of the clip in seconds. An End of 0 means
+	// MaxGIFDuration after Start, or the end of the video if sooner.
+	Start float64
+	End   float64
+
+	// FPS defaults to 12
+	FPS int
+
+	// Width in pixels, 480 by default; smaller videos are never upscaled
+	Width int
+
+	// Loop follows ffmpeg: 0 loops forever (the default), -1 plays once and
+	// n repeats n more times
+	Loop int
+
+	// Format is "gif" (the default) or "webp" for an animated WebP
+	Format string
+
+	// Quality for WebP
+	Quality int
+}
+
+// ConvertVideoToGIF saves input to a temporary file and converts it with
+// ConvertVideoFileToGIF
+func ConvertVideoToGIF(ctx context.Context, input io.Reader, opts VideoToGIFOptions) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "video-gif-input-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inputPath := filepath.Join(tmpDir, "input")
+	f, err := os.Create(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	_, err = io.Copy(f, input)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write video: %w", err)
+	}
+
+	return ConvertVideoFileToGIF(ctx, inputPath, opts)
+}
+
+// ConvertVideoFileToGIF turns a clip of a video into an animated GIF using
+// ffmpeg's two-pass palettegen/paletteuse, which picks the 256 colours that
+// suit this clip instead of a fixed palette, or into an animated WebP
+func ConvertVideoFileToGIF(ctx context.Context, inputPath string, opts VideoToGIFOptions) ([]byte, error) {
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = "gif"
+	}
+	if format != "gif" && format != "webp" {
+		return nil, fmt.Errorf("unsupported output format: %s", opts.Format)
+	}
+
+	if opts.FPS == 0 {
+		opts.FPS = 12
+	}
+	if opts.FPS < 1 || opts.FPS > MaxGIFFPS {
+		return nil, fmt.Errorf("frame rate must be between 1 and %d", MaxGIFFPS)
+	}
+	if opts.Width == 0 {
+		opts.Width = 480
+	}
+	if opts.Width < 16 || opts.Width > MaxGIFWidth {
+		return nil, fmt.Errorf("width must be between 16 and %d pixels", MaxGIFWidth)
+	}
+	if opts.Loop < -1 {
+		return nil, fmt.Errorf("invalid loop count: %d", opts.Loop)
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		opts.Quality = 75
+	}
+
+	if opts.Start < 0 {
+		return nil, fmt.Errorf("start time cannot be negative")
+	}
+	if opts.End == 0 {
+		opts.End = opts.Start + MaxGIFDuration
+	}
+	duration := opts.End - opts.Start
+	if duration <= 0 {
+		return nil, fmt.Errorf("end time must be after the start time")
+	}
+	if duration > MaxGIFDuration {
+		return nil, fmt.Errorf("clips are limited to %d seconds", MaxGIFDuration)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "video-gif-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// seeking before -i is fast and, with re-encoding, still frame accurate
+	input := []string{
+		"-ss", formatSeconds(opts.Start),
+		"-t", formatSeconds(duration),
+		"-i", inputPath,
+	}
+	scale := fmt.Sprintf("fps=%d,scale='min(%d,iw)':-1:flags=lanczos", opts.FPS, opts.Width)
+
+	outputPath := filepath.Join(tmpDir, "output."+format)
+
+	if format == "webp" {
+		loop := opts.Loop
+		// WebP counts plays rather than repeats
+		if loop != 0 {
+			loop++
+		}
+
+		args := append(slices.Clone(input),
+			"-vf", scale,
+			"-an",
+			"-c:v", "libwebp",
+			"-q:v", strconv.Itoa(opts.Quality),
+			"-loop", strconv.Itoa(loop),
+			outputPath,
+		)
+		if err := runFFmpeg(ctx, tmpDir, args...); err != nil {
+			return nil, err
+		}
+	} else {
+		palettePath := filepath.Join(tmpDir, "palette.png")
+
+		// stats_mode=diff favours colours in the parts that move
+		args := append(slices.Clone(input),
+			"-vf", scale+",palettegen=stats_mode=diff",
+			palettePath,
+		)
+		if err := runFFmpeg(ctx, tmpDir, args...); err != nil {
+			return nil, err
+		}
+
+		// diff_mode=rectangle only re-dithers the changed area of each
+		// frame, which keeps static backgrounds from shimmering
+		args = append(slices.Clone(input),
+			"-i", palettePath,
+			"-lavfi", scale+"[x];[x][1:v]paletteuse=dither=bayer:bayer_scale=5:diff_mode=rectangle",
+			"-loop", strconv.Itoa(opts.Loop),
+			outputPath,
+		)
+		if err := runFFmpeg(ctx, tmpDir, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg produced no output; check the start time is within the video")
+	}
+	if info.Size() > MaxGIFOutputSize {
+		return nil, fmt.Errorf("%w: the animation is %d MB, the limit is %d MB; try a shorter clip, lower frame rate or smaller width",
+			ErrImageTooLarge, info.Size()>>20, MaxGIFOutputSize>>20)
+	}
+
+	return os.ReadFile(outputPath)
+}
+
+// runFFmpeg is the single entry point for every ffmpeg invocation. It runs
+// in dir without stdin, overwrites outputs, and only lets ffmpeg open local
+// files, so a crafted upload such as an HLS playlist cannot make the server
+// fetch URLs or read other files. ctx is expected to carry the request's
+// deadline.
+func runFFmpeg(ctx context.Context, dir string, args ...string) error {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return ErrFFmpegUnavailable
+	}
+
+	base := []string{
+		"-hide_banner",
+		"-nostdin",
+		"-loglevel", "error",
+		"-protocol_whitelist", "file",
+		"-y",
+	}
+
+	cmd := exec.CommandContext(ctx, ffmpegPath, append(base, args...)...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("the conversion took too long; try a shorter clip, lower frame rate or smaller width")
+		}
+		return fmt.Errorf("ffmpeg failed: %w\nError: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// ParseTimestamp reads seconds ("90", "12.5") or clock time ("1:30",
+// "01:02:03.5")
+func ParseTimestamp(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time: %q", s)
+	}
+
+	var seconds float64
+	for i, part := range parts {
+		// ParseFloat accepts "NaN" and "Inf", which slip past every range check
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v < 0 || (i > 0 && v >= 60) {
+			return 0, fmt.Errorf("invalid time: %q", s)
+		}
+		seconds = seconds*60 + v
+	}
+
+	return seconds, nil
+}
+
+func formatSeconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
@@ -0,0 +1,44 @@
+package services
+
+import "testing"
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{input: "", want: 0},
+		{input: "90", want: 90},
+		{input: " 12.5 ", want: 12.5},
+		{input: "1:30", want: 90},
+		{input: "01:02:03.5", want: 3723.5},
+		{input: "0:59.9", want: 59.9},
+		{input: "1:60", wantErr: true},
+		{input: "1:2:3:4", wantErr: true},
+		{input: "-5", wantErr: true},
+		{input: "1:-5", wantErr: true},
+		{input: "1:", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "NaN", wantErr: true},
+		{input: "Inf", wantErr: true},
+		{input: "1:+Inf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTimestamp(%q) = %v, want an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTimestamp(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTimestamp(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,534 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrPlaylistBusy is returned when MaxPlaylistJobs downloads are already
+// running, MaxPlaylistDiskBytes is used up or the server is shutting down
+var ErrPlaylistBusy = errors.New("the playlist downloader is busy, please try again later")
+
+const (
+	// MaxPlaylistEntries is the most entries listed from a playlist or channel
+	MaxPlaylistEntries = 200
+
+	// MaxPlaylistItems is the most entries downloaded by one job
+	MaxPlaylistItems = 50
+
+	// MaxPlaylistJobs is the most jobs downloading at the same time
+	MaxPlaylistJobs = 3
+
+	// MaxPlaylistDiskBytes caps the disk space held by every job together.
+	// Running jobs count their whole MaxTotalSize and finished jobs their
+	// files, which stay on disk until playlistJobTTL has passed.
+	MaxPlaylistDiskBytes = 10 << 30
+
+	// playlistJobTTL is how long finished jobs and their files are kept
+	playlistJobTTL = time.Hour
+
+	// playlistJobTimeout bounds a whole job
+	playlistJobTimeout = 2 * time.Hour
+)
+
+type PlaylistInfo struct {
+	Title    string          `json:"title"`
+	Uploader string          `json:"uploader"`
+	Entries  []PlaylistEntry `json:"entries"`
+}
+
+type PlaylistEntry struct {
+	// Index is the 1-based position in the playlist
+	Index    int     `json:"index"`
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	URL      string  `json:"url"`
+	Duration float64 `json:"duration"`
+
+	// FileSize is only known for some sites; it is 0 otherwise
+	FileSize int64 `json:"filesize"`
+}
+
+// GetPlaylistInfo lists the first MaxPlaylistEntries entries of a playlist or
+// channel without resolving each video, which keeps it fast
+func GetPlaylistInfo(ctx context.Context, playlistURL string) (*PlaylistInfo, error) {
+	ytDlpPath, err := exec.LookPath("yt-dlp")
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp not found: %w (install with: pip install yt-dlp)", err)
+	}
+
+	if !isValidURL(playlistURL) {
+		return nil, fmt.Errorf("invalid URL: %s", playlistURL)
+	}
+
+	args := []string{
+		"--flat-playlist",
+		"--dump-single-json",
+		"--playlist-end", strconv.Itoa(MaxPlaylistEntries),
+		"--no-check-certificate",
+		playlistURL,
+	}
+
+	cmd := exec.CommandContext(ctx, ytDlpPath, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to get playlist info: %w\nError: %s", err, stderr.String())
+	}
+
+	var raw struct {
+		Title    string `json:"title"`
+		Uploader string `json:"uploader"`
+		Entries  []struct {
+			ID             string  `json:"id"`
+			Title          string  `json:"title"`
+			URL            string  `json:"url"`
+			WebpageURL     string  `json:"webpage_url"`
+			Duration       float64 `json:"duration"`
+			FileSize       int64   `json:"filesize"`
+			FileSizeApprox int64   `json:"filesize_approx"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse playlist info: %w", err)
+	}
+
+	if len(raw.Entries) == 0 {
+		return nil, fmt.Errorf("no playlist entries found; is this a playlist or channel URL?")
+	}
+
+	info := &PlaylistInfo{Title: raw.Title, Uploader: raw.Uploader}
+	for i, e := range raw.Entries {
+		entry := PlaylistEntry{
+			Index:    i + 1,
+			ID:       e.ID,
+			Title:    e.Title,
+			URL:      e.WebpageURL,
+			Duration: e.Duration,
+			FileSize: e.FileSize,
+		}
+		if entry.URL == "" {
+			entry.URL = e.URL
+		}
+		if entry.FileSize == 0 {
+			entry.FileSize = e.FileSizeApprox
+		}
+		if entry.Title == "" {
+			entry.Title = e.ID
+		}
+		info.Entries = append(info.Entries, entry)
+	}
+
+	return info, nil
+}
+
+// ParsePlaylistSelection reads 1-based entry numbers and ranges such as
+// "1-5, 8, 10-12" and returns the sorted, de-duplicated indexes. An empty
+// selection means every entry.
+func ParsePlaylistSelection(spec string, count int) ([]int, error) {
+	var indexes []int
+
+	if strings.TrimSpace(spec) == "" {
+		for i := 1; i <= count; i++ {
+			indexes = append(indexes, i)
+		}
+	}
+
+	for _, field := range strings.Split(spec, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(field, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection: %q", field)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+				return nil, fmt.Errorf("invalid selection: %q", field)
+			}
+		}
+		if start < 1 || end < start || end > count {
+			return nil, fmt.Errorf("selection %q is outside entries 1-%d", field, count)
+		}
+
+		for i := start; i <= end; i++ {
+			indexes = append(indexes, i)
+		}
+	}
+
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no entries selected")
+	}
+	if len(indexes) > MaxPlaylistItems {
+		return nil, fmt.Errorf("at most %d entries can be downloaded at once, %d were selected", MaxPlaylistItems, len(indexes))
+	}
+
+	return indexes, nil
+}
+
+type PlaylistDownloadOptions struct {
+	Title   string
+	Entries []PlaylistEntry
+
+	// Download is applied to every entry; its URL is ignored
+	Download VideoDownloadOptions
+
+	// MaxTotalSize stops the job once this many bytes are downloaded; the
+	// remaining entries are skipped
+	MaxTotalSize int64
+
+	// MaxTotalDuration, in seconds, is checked against the listed durations
+	// before the job starts
+	MaxTotalDuration float64
+}
+
+// PlaylistItem is the status of one entry in a PlaylistJob
+type PlaylistItem struct {
+	Index    int     `json:"index"`
+	Title    string  `json:"title"`
+	Duration float64 `json:"duration"`
+
+	// Status is "pending", "downloading", "done", "failed" or "skipped"
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	FileName string `json:"file_name,omitempty"`
+	Size     int64  `json:"size"`
+
+	url  string
+	path string
+}
+
+type PlaylistJob struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+
+	// Status is "running" or "done"
+	Status    string         `json:"status"`
+	Items     []PlaylistItem `json:"items"`
+	TotalSize int64          `json:"total_size"`
+	CreatedAt time.Time      `json:"created_at"`
+
+	finishedAt time.Time
+
+	// reserved is the disk space set aside while the job runs
+	reserved int64
+	cancel   context.CancelFunc
+}
+
+// PlaylistJobManager runs playlist downloads in the background, one entry at
+// a time, and keeps the files for playlistJobTTL after each job finishes
+type PlaylistJobManager struct {
+	mu     sync.Mutex
+	jobs   map[string]*PlaylistJob
+	closed bool
+
+	// running tracks the run goroutines so Shutdown can wait for them
+	running sync.WaitGroup
+	done    chan struct{}
+}
+
+func NewPlaylistJobManager() *PlaylistJobManager {
+	m := &PlaylistJobManager{
+		jobs: make(map[string]*PlaylistJob),
+		done: make(chan struct{}),
+	}
+
+	go m.cleanup()
+
+	return m
+}
+
+// Start validates the options and begins downloading in the background,
+// returning the new job's initial status
+func (m *PlaylistJobManager) Start(opts PlaylistDownloadOptions) (*PlaylistJob, error) {
+	if len(opts.Entries) == 0 {
+		return nil, fmt.Errorf("no entries selected")
+	}
+	if len(opts.Entries) > MaxPlaylistItems {
+		return nil, fmt.Errorf("at most %d entries can be downloaded at once", MaxPlaylistItems)
+	}
+
+	var totalDuration float64
+	for _, entry := range opts.Entries {
+		if !isValidURL(entry.URL) {
+			return nil, fmt.Errorf("entry %d has no downloadable URL", entry.Index)
+		}
+		totalDuration += entry.Duration
+	}
+	if opts.MaxTotalDuration > 0 && totalDuration > opts.MaxTotalDuration {
+		return nil, fmt.Errorf("the selected entries last %s, the limit is %s; select fewer entries",
+			time.Duration(totalDuration)*time.Second, time.Duration(opts.MaxTotalDuration)*time.Second)
+	}
+
+	job := &PlaylistJob{
+		ID:        uuid.NewString(),
+		Title:     opts.Title,
+		Status:    "running",
+		CreatedAt: time.Now(),
+	}
+	for _, entry := range opts.Entries {
+		job.Items = append(job.Items, PlaylistItem{
+			Index:    entry.Index,
+			Title:    entry.Title,
+			Duration: entry.Duration,
+			Status:   "pending",
+			url:      entry.URL,
+		})
+	}
+
+	// A job without its own size limit is bounded by the whole budget
+	if opts.MaxTotalSize <= 0 || opts.MaxTotalSize > MaxPlaylistDiskBytes {
+		opts.MaxTotalSize = MaxPlaylistDiskBytes
+	}
+	job.reserved = opts.MaxTotalSize
+
+	m.mu.Lock()
+	running, used := 0, int64(0)
+	for _, j := range m.jobs {
+		if j.Status == "running" {
+			running++
+			used += j.reserved
+		} else {
+			used += j.TotalSize
+		}
+	}
+	if m.closed || running >= MaxPlaylistJobs || used+job.reserved > MaxPlaylistDiskBytes {
+		m.mu.Unlock()
+		return nil, ErrPlaylistBusy
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), playlistJobTimeout)
+	job.cancel = cancel
+	m.jobs[job.ID] = job
+	snapshot := job.snapshot()
+	m.running.Add(1)
+	m.mu.Unlock()
+
+	go m.run(ctx, job, opts)
+
+	return snapshot, nil
+}
+
+// Cancel stops a running job; entries already downloaded are kept
+func (m *PlaylistJobManager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return fmt.Errorf("download not found; it may have expired")
+	}
+	job.cancel()
+	return nil
+}
+
+// Shutdown cancels every running job, waits for them to stop and removes
+// all downloaded files. Start fails with ErrPlaylistBusy afterwards.
+func (m *PlaylistJobManager) Shutdown() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.done)
+	for _, job := range m.jobs {
+		job.cancel()
+	}
+	m.mu.Unlock()
+
+	m.running.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, job := range m.jobs {
+		job.removeFiles()
+		delete(m.jobs, id)
+	}
+}
+
+// Get returns a copy of the job's current status
+func (m *PlaylistJobManager) Get(id string) (*PlaylistJob, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return job.snapshot(), true
+}
+
+// File returns the downloaded file for the entry with the given playlist
+// index
+func (m *PlaylistJobManager) File(id string, index int) (ArchiveFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return ArchiveFile{}, fmt.Errorf("download not found; it may have expired")
+	}
+
+	for _, item := range job.Items {
+		if item.Index == index && item.path != "" {
+			return item.archiveFile(), nil
+		}
+	}
+	return ArchiveFile{}, fmt.Errorf("entry %d has not been downloaded", index)
+}
+
+// Files returns every file downloaded so far
+func (m *PlaylistJobManager) Files(id string) ([]ArchiveFile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("download not found; it may have expired")
+	}
+
+	var files []ArchiveFile
+	for _, item := range job.Items {
+		if item.path != "" {
+			files = append(files, item.archiveFile())
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files have been downloaded yet")
+	}
+	return files, nil
+}
+
+func (m *PlaylistJobManager) run(ctx context.Context, job *PlaylistJob, opts PlaylistDownloadOptions) {
+	defer m.running.Done()
+	defer job.cancel()
+
+	for i := range job.Items {
+		m.mu.Lock()
+		item := &job.Items[i]
+		remaining := opts.MaxTotalSize - job.TotalSize
+		if opts.MaxTotalSize > 0 && remaining <= 0 {
+			item.Status = "skipped"
+			item.Error = "total size limit reached"
+			m.mu.Unlock()
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			item.Status = "skipped"
+			item.Error = "the download was cancelled"
+			if errors.Is(err, context.DeadlineExceeded) {
+				item.Error = "the download took too long"
+			}
+			m.mu.Unlock()
+			continue
+		}
+		item.Status = "downloading"
+		url := item.url
+		m.mu.Unlock()
+
+		downloadOpts := opts.Download
+		downloadOpts.URL = url
+		if opts.MaxTotalSize > 0 && (downloadOpts.MaxFileSize == 0 || remaining < downloadOpts.MaxFileSize) {
+			downloadOpts.MaxFileSize = remaining
+		}
+
+		path, err := DownloadVideo(ctx, downloadOpts)
+
+		var size int64
+		if err == nil {
+			if info, statErr := os.Stat(path); statErr == nil {
+				size = info.Size()
+			}
+		}
+
+		m.mu.Lock()
+		if err != nil {
+			item.Status = "failed"
+			item.Error = err.Error()
+		} else {
+			item.Status = "done"
+			item.FileName = filepath.Base(path)
+			item.Size = size
+			item.path = path
+			job.TotalSize += size
+		}
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	job.Status = "done"
+	job.finishedAt = time.Now()
+	m.mu.Unlock()
+}
+
+// cleanup removes jobs, and their files, playlistJobTTL after they finish
+func (m *PlaylistJobManager) cleanup() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		}
+
+		m.mu.Lock()
+		now := time.Now()
+		for id, job := range m.jobs {
+			if job.Status != "done" || now.Sub(job.finishedAt) < playlistJobTTL {
+				continue
+			}
+			job.removeFiles()
+			delete(m.jobs, id)
+		}
+		m.mu.Unlock()
+	}
+}
+
+func (j *PlaylistJob) removeFiles() {
+	for _, item := range j.Items {
+		if item.path != "" {
+			CleanupDownloadedFile(item.path)
+		}
+	}
+}
+
+// snapshot copies the job so it can be read without holding the lock
+func (j *PlaylistJob) snapshot() *PlaylistJob {
+	c := *j
+	c.Items = slices.Clone(j.Items)
+	return &c
+}
+
+// archiveFile names the file with its playlist index so files sort in
+// playlist order
+func (item PlaylistItem) archiveFile() ArchiveFile {
+	return ArchiveFile{
+		Name: fmt.Sprintf("%03d - %s", item.Index, item.FileName),
+		Path: item.path,
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePlaylistSelection(t *testing.T) {
+	tests := []struct {
+		spec    string
+		count   int
+		want    []int
+		wantErr bool
+	}{
+		{spec: "", count: 3, want: []int{1, 2, 3}},
+		{spec: "  ", count: 2, want: []int{1, 2}},
+		{spec: "2", count: 5, want: []int{2}},
+		{spec: "1-3, 5", count: 5, want: []int{1, 2, 3, 5}},
+		{spec: "5,1-2,2", count: 5, want: []int{1, 2, 5}},
+		{spec: "4 - 5,", count: 5, want: []int{4, 5}},
+		{spec: "3-3", count: 3, want: []int{3}},
+		{spec: "0", count: 5, wantErr: true},
+		{spec: "6", count: 5, wantErr: true},
+		{spec: "4-2", count: 5, wantErr: true},
+		{spec: "2-9", count: 5, wantErr: true},
+		{spec: "-3", count: 5, wantErr: true},
+		{spec: "a", count: 5, wantErr: true},
+		{spec: "1-b", count: 5, wantErr: true},
+		{spec: ",", count: 5, wantErr: true},
+		{spec: "", count: 0, wantErr: true},
+		{spec: "1-51", count: 60, wantErr: true},
+		{spec: "", count: MaxPlaylistItems + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePlaylistSelection(tt.spec, tt.count)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePlaylistSelection(%q, %d) = %v, want an error", tt.spec, tt.count, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePlaylistSelection(%q, %d): %v", tt.spec, tt.count, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParsePlaylistSelection(%q, %d) = %v, want %v", tt.spec, tt.count, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SubtitleLanguage is a subtitle track a site offers for a video
+type SubtitleLanguage struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+
+	// Auto is true for automatically generated captions
+	Auto    bool     `json:"auto"`
+	Formats []string `json:"formats"`
+}
+
+type SubtitleDownloadOptions struct {
+	URL  string
+	Lang string
+
+	// Auto downloads the automatically generated captions; otherwise the
+	// uploaded subtitles are preferred, falling back to automatic ones
+	Auto bool
+
+	CookiesFromBrowser string
+	CookiesPath        string
+}
+
+// SubtitleTrack is a downloaded subtitle track
+type SubtitleTrack struct {
+	// Auto is true when yt-dlp wrote automatically generated captions, even
+	// if uploaded subtitles were requested
+	Auto bool
+	Cues []SubtitleCue
+}
+
+// SubtitleCue is a single timed piece of subtitle text
+type SubtitleCue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// SubtitleContentTypes maps the subtitle output formats to their MIME types
+var SubtitleContentTypes = map[string]string{
+	"vtt": "text/vtt; charset=utf-8",
+	"srt": "application/x-subrip; charset=utf-8",
+	"ass": "text/x-ssa; charset=utf-8",
+	"txt": "text/plain; charset=utf-8",
+}
+
+var (
+	// subtitleLangPattern matches language codes such as "en", "pt-BR" or
+	// "en-orig"; --sub-langs takes regexes, which must not reach yt-dlp
+	subtitleLangPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,20}$`)
+
+	subtitleTimingPattern = regexp.MustCompile(`^\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})`)
+	subtitleTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	assOverridePattern    = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+// subtitleLanguages lists the tracks in yt-dlp's "subtitles" and
+// "automatic_captions", uploaded subtitles first
+func subtitleLanguages(data []byte) []SubtitleLanguage {
+	type track struct {
+		Ext  string `json:"ext"`
+		Name string `json:"name"`
+	}
+	var raw struct {
+		Subtitles         map[string][]track `json:"subtitles"`
+		AutomaticCaptions map[string][]track `json:"automatic_captions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	var langs []SubtitleLanguage
+	for _, group := range []struct {
+		tracks map[string][]track
+		auto   bool
+	}{{raw.Subtitles, false}, {raw.AutomaticCaptions, true}} {
+		start := len(langs)
+		for code, tracks := range group.tracks {
+			// live chat replays are listed as a subtitle track
+			if code == "live_chat" || len(tracks) == 0 {
+				continue
+			}
+			lang := SubtitleLanguage{Code: code, Name: tracks[0].Name, Auto: group.auto}
+			if lang.Name == "" {
+				lang.Name = code
+			}
+			for _, t := range tracks {
+				lang.Formats = append(lang.Formats, t.Ext)
+			}
+			langs = append(langs, lang)
+		}
+		sort.Slice(langs[start:], func(i, j int) bool {
+			return langs[start+i].Code < langs[start+j].Code
+		})
+	}
+
+	return langs
+}
+
+// DownloadSubtitles fetches one subtitle track without the video and parses
+// it into cues. Tracks in other formats are converted to WebVTT by yt-dlp
+// first, which needs ffmpeg.
+func DownloadSubtitles(ctx context.Context, opts SubtitleDownloadOptions) (*SubtitleTrack, error) {
+	ytDlpPath, err := exec.LookPath("yt-dlp")
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp not found: %w (install with: pip install yt-dlp)", err)
+	}
+
+	if !isValidURL(opts.URL) {
+		return nil, fmt.Errorf("invalid URL: %s", opts.URL)
+	}
+	if !subtitleLangPattern.MatchString(opts.Lang) || opts.Lang == "all" {
+		return nil, fmt.Errorf("invalid subtitle language: %q", opts.Lang)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "subtitles-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// the info JSON tells which track was written: yt-dlp only falls back to
+	// automatic captions for languages without uploaded subtitles
+	args := []string{
+		"--skip-download",
+		"--dump-json",
+		"--no-simulate",
+		"--no-playlist",
+		"--no-check-certificate",
+		"--sub-langs", opts.Lang,
+		"--sub-format", "vtt/srt/best",
+		"--convert-subs", "vtt",
+		"-o", filepath.Join(tmpDir, "subtitles.%(ext)s"),
+	}
+	if opts.Auto {
+		args = append(args, "--write-auto-subs")
+	} else {
+		args = append(args, "--write-subs", "--write-auto-subs")
+	}
+
+	if _, err := exec.LookPath("node"); err == nil {
+		args = append(args, "--js-runtimes", "node")
+	} else if _, err := exec.LookPath("deno"); err == nil {
+		args = append(args, "--js-runtimes", "deno")
+	}
+
+	// cookies: prefer CookiesFromBrowser, then CookiesPath, then env
+	if IsYouTubeURL(opts.URL) {
+		if opts.CookiesFromBrowser != "" {
+			args = append(args, "--cookies-from-browser", opts.CookiesFromBrowser)
+		} else {
+			cookies := opts.CookiesPath
+			if cookies == "" {
+				cookies = os.Getenv("YTDLP_COOKIES")
+			}
+			if cookies != "" {
+				args = append(args, "--cookies", cookies)
+			}
+		}
+	}
+
+	args = append(args, opts.URL)
+
+	cmd := exec.CommandContext(ctx, ytDlpPath, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("subtitle download failed: %w\nError: %s", err, stderr.String())
+	}
+
+	var info struct {
+		Subtitles map[string]json.RawMessage `json:"subtitles"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse video info: %w", err)
+	}
+	_, uploaded := info.Subtitles[opts.Lang]
+
+	matches, _ := filepath.Glob(filepath.Join(tmpDir, "*.vtt"))
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no %s subtitles are available for this video", opts.Lang)
+	}
+
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subtitles: %w", err)
+	}
+
+	cues, err := ParseSubtitles(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubtitleTrack{Auto: opts.Auto || !uploaded, Cues: cues}, nil
+}
+
+// ParseSubtitles reads WebVTT, SRT or ASS/SSA subtitles. Styling and inline
+// tags are dropped, leaving plain text cues.
+func ParseSubtitles(data []byte) ([]SubtitleCue, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var cues []SubtitleCue
+	var err error
+	if strings.Contains(text, "[Events]") {
+		cues, err = parseASS(text)
+	} else {
+		// WebVTT and SRT share the same cue layout apart from the header and
+		// the decimal separator
+		cues, err = parseTimedBlocks(text)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("no subtitle cues found")
+	}
+	return cues, nil
+}
+
+func parseTimedBlocks(text string) ([]SubtitleCue, error) {
+	var cues []SubtitleCue
+
+	for _, block := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+
+		// the timing line follows an optional cue identifier; blocks without
+		// one are headers, NOTE, STYLE or REGION blocks
+		timing := -1
+		for i, line := range lines {
+			if subtitleTimingPattern.MatchString(line) {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 {
+			continue
+		}
+
+		m := subtitleTimingPattern.FindStringSubmatch(lines[timing])
+		start, err := parseSubtitleTime(m[1])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseSubtitleTime(m[2])
+		if err != nil {
+			return nil, err
+		}
+
+		var textLines []string
+		for _, line := range lines[timing+1:] {
+			line = html.UnescapeString(subtitleTagPattern.ReplaceAllString(line, ""))
+			if line = strings.TrimSpace(line); line != "" {
+				textLines = append(textLines, line)
+			}
+		}
+
+		cues = append(cues, SubtitleCue{Start: start, End: end, Text: strings.Join(textLines, "\n")})
+	}
+
+	return cues, nil
+}
+
+func parseASS(text string) ([]SubtitleCue, error) {
+	var cues []SubtitleCue
+
+	// the Format line names the comma separated fields; Text is always last
+	// and may itself contain commas
+	fields := []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+	inEvents := false
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			inEvents = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !inEvents {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "format":
+			fields = fields[:0]
+			for _, f := range strings.Split(value, ",") {
+				fields = append(fields, strings.ToLower(strings.TrimSpace(f)))
+			}
+		case "dialogue":
+			values := strings.SplitN(value, ",", len(fields))
+			if len(values) < len(fields) {
+				continue
+			}
+
+			var cue SubtitleCue
+			for i, field := range fields {
+				v := strings.TrimSpace(values[i])
+				var err error
+				switch field {
+				case "start":
+					cue.Start, err = parseSubtitleTime(v)
+				case "end":
+					cue.End, err = parseSubtitleTime(v)
+				case "text":
+					v = assOverridePattern.ReplaceAllString(v, "")
+					v = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(v)
+					cue.Text = strings.TrimSpace(v)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			cues = append(cues, cue)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read subtitles: %w", err)
+	}
+
+	sort.SliceStable(cues, func(i, j int) bool { return cues[i].Start < cues[j].Start })
+	return cues, nil
+}
+
+// parseSubtitleTime reads "HH:MM:SS.mmm", "MM:SS.mmm", SRT's "HH:MM:SS,mmm"
+// and ASS's "H:MM:SS.cc"
+func parseSubtitleTime(s string) (time.Duration, error) {
+	clock, frac, _ := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+
+	seconds, err := ParseTimestamp(clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid subtitle time: %q", s)
+	}
+
+	d := time.Duration(seconds) * time.Second
+	if frac != "" {
+		// "5" is 500ms and "05" is 50ms
+		ms, err := strconv.Atoi((frac + "00")[:3])
+		if err != nil {
+			return 0, fmt.Errorf("invalid subtitle time: %q", s)
+		}
+		d += time.Duration(ms) * time.Millisecond
+	}
+	return d, nil
+}
+
+// CleanSubtitleCues removes the repetition in automatic captions, where each
+// cue repeats the line shown by the cue before it and short cues exist only
+// to scroll the text. Lines already shown by the previous cue are dropped,
+// along with cues left empty. Uploaded subtitles can repeat lines on
+// purpose, so only use it on tracks with SubtitleTrack.Auto set.
+func CleanSubtitleCues(cues []SubtitleCue) []SubtitleCue {
+	var cleaned []SubtitleCue
+	var previous []string
+
+	for _, cue := range cues {
+		var lines []string
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			if containsLine(previous, line) || containsLine(lines, line) {
+				continue
+			}
+			lines = append(lines, line)
+		}
+		if cue.Text != "" {
+			previous = strings.Split(cue.Text, "\n")
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		cue.Text = strings.Join(lines, "\n")
+		cleaned = append(cleaned, cue)
+	}
+
+	return cleaned
+}
+
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
+
+// SubtitleTranscript joins the cues into plain text, starting a new
+// paragraph wherever the speech pauses for more than two seconds
+func SubtitleTranscript(cues []SubtitleCue) string {
+	var sb strings.Builder
+
+	for i, cue := range cues {
+		if i > 0 {
+			if cue.Start-cues[i-1].End > 2*time.Second {
+				sb.WriteString("\n\n")
+			} else {
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString(strings.Join(strings.Fields(cue.Text), " "))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// WriteSubtitles renders cues as "vtt", "srt", "ass" or a "txt" transcript
+func WriteSubtitles(cues []SubtitleCue, format string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch format {
+	case "vtt":
+		buf.WriteString("WEBVTT\n\n")
+		for _, cue := range cues {
+			fmt.Fprintf(&buf, "%s --> %s\n%s\n\n", formatSubtitleTime(cue.Start, "."), formatSubtitleTime(cue.End, "."), cue.Text)
+		}
+	case "srt":
+		for i, cue := range cues {
+			fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", i+1, formatSubtitleTime(cue.Start, ","), formatSubtitleTime(cue.End, ","), cue.Text)
+		}
+	case "ass":
+		buf.WriteString(assHeader)
+		for _, cue := range cues {
+			text := strings.ReplaceAll(cue.Text, "\n", `\N`)
+			fmt.Fprintf(&buf, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", formatASSTime(cue.Start), formatASSTime(cue.End), text)
+		}
+	case "txt":
+		buf.WriteString(SubtitleTranscript(cues))
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+const assHeader = `[Script Info]
+ScriptType: v4.00+
+PlayResX: 1920
+PlayResY: 1080
+WrapStyle: 0
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,64,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,3,1,2,60,60,50,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+// formatSubtitleTime writes HH:MM:SS.mmm with the given decimal separator
+func formatSubtitleTime(d time.Duration, sep string) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// formatASSTime writes H:MM:SS.cc
+func formatASSTime(d time.Duration) string {
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSubtitles(t *testing.T) {
+	ms := time.Millisecond
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []SubtitleCue
+		wantErr bool
+	}{
+		{
+			name: "WebVTT",
+			input: "\ufeffWEBVTT\nKind: captions\n\nNOTE a comment\n\n" +
+				"intro\n00:00:01.000 --> 00:00:02.500 align:start\n<c>Hello</c> &amp; <b>welcome</b>\n\n" +
+				"00:01.5 --> 00:03.25\nsecond\nline\n",
+			want: []SubtitleCue{
+				{Start: time.Second, End: 2500 * ms, Text: "Hello & welcome"},
+				{Start: 1500 * ms, End: 3250 * ms, Text: "second\nline"},
+			},
+		},
+		{
+			name:  "SRT with CRLF",
+			input: "1\r\n00:00:01,000 --> 00:00:02,000\r\nfirst\r\n\r\n2\r\n01:00:00,050 --> 01:00:01,000\r\n<i>second</i>\r\n",
+			want: []SubtitleCue{
+				{Start: time.Second, End: 2 * time.Second, Text: "first"},
+				{Start: time.Hour + 50*ms, End: time.Hour + time.Second, Text: "second"},
+			},
+		},
+		{
+			name: "ASS",
+			input: "[Script Info]\nTitle: x\n\n[V4+ Styles]\nFormat: Name, Fontname\n\n[Events]\n" +
+				"Format: Layer, Start, End, Style, Text\n" +
+				"Dialogue: 0,0:00:05.00,0:00:06.50,Default,later\n" +
+				"Comment: 0,0:00:00.00,0:00:01.00,Default,ignored\n" +
+				"Dialogue: 0,0:00:01.20,0:00:02.00,Default,{\\i1}one,\\Ntwo\\hthree\n",
+			want: []SubtitleCue{
+				{Start: 1200 * ms, End: 2 * time.Second, Text: "one,\ntwo three"},
+				{Start: 5 * time.Second, End: 6500 * ms, Text: "later"},
+			},
+		},
+		{name: "no cues", input: "WEBVTT\n\nNOTE nothing here\n", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "ASS without dialogue", input: "[Events]\nFormat: Start, End, Text\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSubtitles([]byte(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSubtitles: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSubtitlesRoundTrip(t *testing.T) {
+	cues := []SubtitleCue{
+		{Start: 1500 * time.Millisecond, End: 3 * time.Second, Text: "one"},
+		{Start: time.Hour + 10*time.Millisecond, End: time.Hour + time.Second, Text: "two\nlines"},
+	}
+
+	for _, format := range []string{"vtt", "srt", "ass"} {
+		data, err := WriteSubtitles(cues, format)
+		if err != nil {
+			t.Fatalf("WriteSubtitles(%s): %v", format, err)
+		}
+		got, err := ParseSubtitles(data)
+		if err != nil {
+			t.Fatalf("ParseSubtitles(%s): %v", format, err)
+		}
+		if !reflect.DeepEqual(got, cues) {
+			t.Errorf("%s: got %+v, want %+v", format, got, cues)
+		}
+	}
+}
+
+func TestCleanSubtitleCues(t *testing.T) {
+	cue := func(text string) SubtitleCue { return SubtitleCue{Text: text} }
+
+	tests := []struct {
+		name  string
+		input []SubtitleCue
+		want  []string
+	}{
+		{
+			name:  "rolling captions",
+			input: []SubtitleCue{cue("hello there"), cue("hello there\nhow are you"), cue("how are you"), cue("how are you\nfine thanks")},
+			want:  []string{"hello there", "how are you", "fine thanks"},
+		},
+		{
+			name:  "repeat after a gap is kept",
+			input: []SubtitleCue{cue("yes"), cue("no"), cue("yes")},
+			want:  []string{"yes", "no", "yes"},
+		},
+		{
+			name:  "empty cue does not reset the previous lines",
+			input: []SubtitleCue{cue("one"), cue(""), cue("one\ntwo")},
+			want:  []string{"one", "two"},
+		},
+		{
+			name:  "duplicate lines within a cue",
+			input: []SubtitleCue{cue(" a \na\n\nb")},
+			want:  []string{"a\nb"},
+		},
+		{
+			name:  "nothing left",
+			input: []SubtitleCue{cue("  ")},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, c := range CleanSubtitleCues(tt.input) {
+			got = append(got, c.Text)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('colorPalette', () => ({
+        // State
+        file: null,
+        preview: null,
+        colors: 6,
+        method: 'kmeans',
+        analyzing: false,
+        error: '',
+        result: null,
+
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            // Validate file size (10MB limit)
+            if (file.size > 10 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 10MB.';
+                return;
+            }
+
+            if (this.preview) URL.revokeObjectURL(this.preview);
+            this.file = file;
+            this.preview = URL.createObjectURL(file);
+            this.error = '';
+            this.result = null;
+        },
+
+        async analyze() {
+            if (!this.file) {
+                this.error = 'Please select an image first';
+                return;
+            }
+
+            this.analyzing = true;
+            this.error = '';
+            this.result = null;
+
+            try {
+                const formData = new FormData();
+                formData.append('image', this.file);
+                formData.append('colors', this.colors);
+                formData.append('method', this.method);
+
+                const response = await fetch('/api/tools/image/palette', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Analysis failed');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+                this.result = data.analysis;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.analyzing = false;
+            }
+        },
+
+        // SVG path for one channel, scaled to the tallest bar of all three
+        histogramPath(channel) {
+            if (!this.result) return '';
+
+            const h = this.result.histogram;
+            const peak = Math.max(...h.red, ...h.green, ...h.blue, 1);
+            let d = 'M0,100';
+            h[channel].forEach((count, i) => {
+                d += ` L${i},${100 - (count / peak) * 100}`;
+            });
+            return d + ' L255,100 Z';
+        },
+
+        async copy(text) {
+            try {
+                await navigator.clipboard.writeText(text);
+            } catch (error) {
+                this.error = 'Failed to copy to clipboard';
+            }
+        },
+
+        downloadJSON() {
+            if (!this.result) return;
+
+            const blob = new Blob([JSON.stringify(this.result, null, 2)], { type: 'application/json' });
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = this.file.name.replace(/\.[^/.]+$/, '') + '_palette.json';
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            setTimeout(() => URL.revokeObjectURL(url), 100);
+        }
+    }));
+});
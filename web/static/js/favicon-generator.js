@@ -0,0 +1,108 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('faviconGenerator', () => ({
+        // State
+        file: null,
+        fileName: '',
+        fileSize: '',
+        name: '',
+        shortName: '',
+        themeColor: '#ffffff',
+        backgroundColor: '#ffffff',
+        maskablePadding: 10,
+        generating: false,
+        error: '',
+        result: null,
+
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            // Validate file size (10MB limit)
+            if (file.size > 10 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 10MB.';
+                return;
+            }
+
+            this.file = file;
+            this.fileName = file.name;
+            this.fileSize = this.formatBytes(file.size);
+            this.error = '';
+            this.result = null;
+        },
+
+        async generate() {
+            if (!this.file) {
+                this.error = 'Please select an image first';
+                return;
+            }
+
+            this.generating = true;
+            this.error = '';
+            this.result = null;
+
+            try {
+                const formData = new FormData();
+                formData.append('image', this.file);
+                formData.append('name', this.name);
+                formData.append('short_name', this.shortName);
+                formData.append('theme_color', this.themeColor);
+                formData.append('background_color', this.backgroundColor);
+                formData.append('maskable_padding', this.maskablePadding);
+
+                const response = await fetch('/api/tools/image/favicon', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to generate icons');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+                this.result = data;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.generating = false;
+            }
+        },
+
+        pngUrl(data) {
+            return data ? 'data:image/png;base64,' + data : '';
+        },
+
+        async copySnippet() {
+            try {
+                await navigator.clipboard.writeText(this.result.snippet);
+            } catch (error) {
+                this.error = 'Failed to copy to clipboard';
+            }
+        },
+
+        download() {
+            if (!this.result) return;
+
+            const link = document.createElement('a');
+            link.href = 'data:application/zip;base64,' + this.result.archive;
+            link.download = 'favicons.zip';
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
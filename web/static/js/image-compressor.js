@@ -0,0 +1,119 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('imageCompressor', () => ({
+        // State
+        file: null,
+        fileName: '',
+        fileSize: '',
+        originalUrl: null,
+        outputFormat: '',
+        targetKB: '',
+        maxDimension: '',
+        quality: 80,
+        colors: 0,
+        allowDownscale: true,
+        compressing: false,
+        error: '',
+        result: null,
+
+        // Handle file selection
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            // Validate file size (10MB limit)
+            if (file.size > 10 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 10MB.';
+                return;
+            }
+
+            if (this.originalUrl) URL.revokeObjectURL(this.originalUrl);
+
+            this.file = file;
+            this.fileName = file.name;
+            this.fileSize = this.formatBytes(file.size);
+            this.originalUrl = URL.createObjectURL(file);
+            this.error = '';
+            this.result = null;
+        },
+
+        // PNG options apply when converting to PNG or keeping a PNG input
+        isPNG() {
+            return this.outputFormat === 'png' || (this.outputFormat === '' && this.file?.type === 'image/png');
+        },
+
+        async compress() {
+            if (!this.file) {
+                this.error = 'Please select an image first';
+                return;
+            }
+
+            this.compressing = true;
+            this.error = '';
+            this.result = null;
+
+            try {
+                const formData = new FormData();
+                formData.append('image', this.file);
+                formData.append('format', this.outputFormat);
+                formData.append('quality', this.quality);
+                if (this.targetKB) formData.append('target_kb', this.targetKB);
+                if (this.maxDimension) formData.append('max_dimension', this.maxDimension);
+                if (this.isPNG() && this.colors) formData.append('colors', this.colors);
+                formData.append('allow_downscale', this.allowDownscale);
+
+                const response = await fetch('/api/tools/image/compress', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Compression failed');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+                this.result = data;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.compressing = false;
+            }
+        },
+
+        resultUrl() {
+            if (!this.result) return '';
+            return 'data:image/' + this.result.format + ';base64,' + this.result.image;
+        },
+
+        savings() {
+            if (!this.result || !this.result.original_size) return 0;
+            return Math.round((1 - this.result.compressed_size / this.result.original_size) * 100);
+        },
+
+        download() {
+            if (!this.result) return;
+
+            const ext = this.result.format === 'jpeg' ? 'jpg' : this.result.format;
+            const link = document.createElement('a');
+            link.href = this.resultUrl();
+            link.download = this.fileName.replace(/\.[^/.]+$/, '') + '_compressed.' + ext;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
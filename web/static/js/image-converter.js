@@ -2,37 +2,165 @@ document.addEventListener('alpine:init', () => {
     Alpine.data('imageConverter', () => ({
         // State
         file: null,
+        files: [],
+        batch: false,
         fileName: '',
         fileSize: '',
         outputFormat: 'jpeg',
         quality: 85,
+        resizeMode: '',
+        resizeWidth: '',
+        resizeHeight: '',
+        resizePercent: 50,
+        cropEnabled: false,
+        cropX: 0,
+        cropY: 0,
+        cropWidth: '',
+        cropHeight: '',
+        rotate: 0,
+        flipHorizontal: false,
+        flipVertical: false,
+        watermarkEnabled: false,
+        watermarkType: 'text',
+        watermarkText: '',
+        watermarkLogo: null,
+        watermarkFontSize: '',
+        watermarkColor: '#ffffff',
+        watermarkScale: 20,
+        watermarkOpacity: 50,
+        watermarkPosition: 'bottom-right',
+        watermarkAngle: 0,
+        watermarkTile: false,
+        isGIF: false,
+        frame: 1,
+        metadata: null,
+        stripping: false,
         converting: false,
         error: '',
         result: null,
         resultFormat: '',
         resultSize: '',
+        resultDimensions: '',
         resultBlob: null,
+        batchResult: null,
 
         // Handle file selection
         handleFileSelect(event) {
-            const file = event.target.files[0];
+            const files = Array.from(event.target.files);
+            const file = files[0];
             if (!file) return;
 
+            // Several files or a ZIP are converted as a batch
+            const isZip = file.type === 'application/zip' || file.name.toLowerCase().endsWith('.zip');
+            if (files.length > 1 || isZip) {
+                const total = files.reduce((sum, f) => sum + f.size, 0);
+                if (total > 50 * 1024 * 1024) {
+                    this.error = 'Files too large. Maximum total size is 50MB.';
+                    return;
+                }
+
+                this.files = files;
+                this.batch = true;
+                this.file = null;
+                this.fileName = files.length === 1 ? file.name : `${files.length} files`;
+                this.fileSize = this.formatBytes(total);
+                this.isGIF = false;
+                this.error = '';
+                this.result = null;
+                this.batchResult = null;
+                this.metadata = null;
+                return;
+            }
+
             // Validate file size (10MB limit)
             if (file.size > 10 * 1024 * 1024) {
                 this.error = 'File too large. Maximum size is 10MB.';
                 return;
             }
 
+            this.files = [];
+            this.batch = false;
+            this.batchResult = null;
+
             this.file = file;
             this.fileName = file.name;
             this.fileSize = this.formatBytes(file.size);
+            this.isGIF = file.type === 'image/gif';
+            this.frame = 1;
             this.error = '';
             this.result = null;
+            this.metadata = null;
+            this.loadMetadata();
+        },
+
+        // Read EXIF/XMP/ICC details so users can see what the file reveals
+        async loadMetadata() {
+            try {
+                const formData = new FormData();
+                formData.append('image', this.file);
+
+                const response = await fetch('/api/tools/image/metadata', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) return;
+
+                const data = await response.json();
+                this.metadata = data.metadata;
+            } catch (error) {
+                console.error(error);
+            }
+        },
+
+        hasMetadata() {
+            if (!this.metadata) return false;
+            return !!(this.metadata.exif || this.metadata.xmp || this.metadata.has_icc);
+        },
+
+        // Download the original with its metadata removed, pixels untouched
+        async stripMetadata() {
+            this.stripping = true;
+            this.error = '';
+
+            try {
+                const formData = new FormData();
+                formData.append('image', this.file);
+                formData.append('mode', 'lossless');
+
+                const response = await fetch('/api/tools/image/strip', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to remove metadata');
+                }
+
+                const blob = await response.blob();
+                const url = URL.createObjectURL(blob);
+                const a = document.createElement('a');
+                a.href = url;
+                const ext = this.fileName.split('.').pop();
+                a.download = `${this.fileName.replace(/\.[^/.]+$/, '')}_clean.${ext}`;
+                document.body.appendChild(a);
+                a.click();
+                document.body.removeChild(a);
+                setTimeout(() => URL.revokeObjectURL(url), 100);
+
+            } catch (error) {
+                this.error = error.message;
+            } finally {
+                this.stripping = false;
+            }
         },
 
         // Convert the image
         async convert() {
+            if (this.batch) {
+                return this.convertBatch();
+            }
             if (!this.file) {
                 this.error = 'Please select an image first';
                 return;
@@ -41,6 +169,7 @@ document.addEventListener('alpine:init', () => {
             this.converting = true;
             this.error = '';
             this.result = null;
+            this.resultDimensions = '';
 
             try {
                 // Create FormData and append our file
@@ -48,6 +177,15 @@ document.addEventListener('alpine:init', () => {
                 formData.append('image', this.file);
                 formData.append('format', this.outputFormat);
                 formData.append('quality', this.quality);
+                if (this.isGIF && this.outputFormat !== 'gif') {
+                    formData.append('frame', this.frame);
+                }
+
+                const operations = this.buildOperations();
+                if (operations.length > 0) {
+                    formData.append('operations', JSON.stringify(operations));
+                }
+                this.appendWatermark(formData);
 
                 const response = await fetch('/api/tools/image/convert', {
                     method: 'POST',
@@ -75,6 +213,111 @@ document.addEventListener('alpine:init', () => {
             }
         },
 
+        // Convert every selected file (or ZIP contents) into one ZIP
+        async convertBatch() {
+            this.converting = true;
+            this.error = '';
+            this.batchResult = null;
+
+            try {
+                const formData = new FormData();
+                this.files.forEach(f => formData.append('images', f));
+                formData.append('format', this.outputFormat);
+                formData.append('quality', this.quality);
+
+                const operations = this.buildOperations();
+                if (operations.length > 0) {
+                    formData.append('operations', JSON.stringify(operations));
+                }
+                this.appendWatermark(formData);
+
+                const response = await fetch('/api/tools/image/convert-batch', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Conversion failed');
+                }
+
+                const blob = await response.blob();
+                this.resultBlob = blob;
+                this.batchResult = {
+                    size: this.formatBytes(blob.size),
+                    format: this.outputFormat.toUpperCase()
+                };
+
+            } catch (error) {
+                this.error = error.message;
+            } finally {
+                this.converting = false;
+            }
+        },
+
+        // Build the edit chain sent to the server
+        buildOperations() {
+            const operations = [];
+
+            if (this.cropEnabled && this.cropWidth && this.cropHeight) {
+                operations.push({
+                    op: 'crop',
+                    x: this.cropX || 0,
+                    y: this.cropY || 0,
+                    width: this.cropWidth,
+                    height: this.cropHeight
+                });
+            }
+
+            if (this.resizeMode === 'percent') {
+                operations.push({ op: 'resize', mode: 'percent', percent: this.resizePercent });
+            } else if (this.resizeMode) {
+                operations.push({
+                    op: 'resize',
+                    mode: this.resizeMode,
+                    width: this.resizeWidth || 0,
+                    height: this.resizeHeight || 0
+                });
+            }
+
+            if (this.rotate) {
+                operations.push({ op: 'rotate', angle: this.rotate });
+            }
+            if (this.flipHorizontal) {
+                operations.push({ op: 'flip', direction: 'horizontal' });
+            }
+            if (this.flipVertical) {
+                operations.push({ op: 'flip', direction: 'vertical' });
+            }
+
+            return operations;
+        },
+
+        // Add the watermark settings and logo, if enabled
+        appendWatermark(formData) {
+            if (!this.watermarkEnabled) return;
+
+            const watermark = {
+                opacity: this.watermarkOpacity / 100,
+                position: this.watermarkPosition,
+                angle: this.watermarkAngle || 0,
+                tile: this.watermarkTile
+            };
+
+            if (this.watermarkType === 'logo' && this.watermarkLogo) {
+                watermark.scale = this.watermarkScale / 100;
+                formData.append('watermark_logo', this.watermarkLogo);
+            } else {
+                watermark.text = this.watermarkText;
+                watermark.color = this.watermarkColor;
+                if (this.watermarkFontSize) {
+                    watermark.font_size = this.watermarkFontSize;
+                }
+            }
+
+            formData.append('watermark', JSON.stringify(watermark));
+        },
+
         // Download the converted image
         download() {
             if (!this.resultBlob) return;
@@ -82,10 +325,20 @@ document.addEventListener('alpine:init', () => {
             const url = URL.createObjectURL(this.resultBlob);
             const a = document.createElement('a');
             a.href = url;
+
+            if (this.batch) {
+                a.download = 'converted-images.zip';
+                document.body.appendChild(a);
+                a.click();
+                document.body.removeChild(a);
+                setTimeout(() => URL.revokeObjectURL(url), 100);
+                return;
+            }
             
             // Generate filename
             const originalName = this.fileName.replace(/\.[^/.]+$/, '');
-            a.download = `${originalName}_converted.${this.outputFormat}`;
+            const ext = this.outputFormat === 'jpeg' ? 'jpg' : this.outputFormat;
+            a.download = `${originalName}_converted.${ext}`;
             
             document.body.appendChild(a);
             a.click();
@@ -0,0 +1,102 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('imageDiff', () => ({
+        // State
+        before: null,
+        after: null,
+        threshold: 0,
+        align: 'resize',
+        comparing: false,
+        error: '',
+        result: null,
+
+        handleFileSelect(event, which) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            // Validate file size (10MB limit)
+            if (file.size > 10 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 10MB.';
+                return;
+            }
+
+            this[which] = file;
+            this.error = '';
+            this.result = null;
+        },
+
+        async compare() {
+            if (!this.before || !this.after) {
+                this.error = 'Please select both images first';
+                return;
+            }
+
+            this.comparing = true;
+            this.error = '';
+            this.result = null;
+
+            try {
+                const formData = new FormData();
+                formData.append('before', this.before);
+                formData.append('after', this.after);
+                formData.append('threshold', this.threshold);
+                formData.append('align', this.align);
+
+                const response = await fetch('/api/tools/image/diff', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Comparison failed');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+                this.result = data;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.comparing = false;
+            }
+        },
+
+        downloadDiff() {
+            if (!this.result) return;
+
+            const link = document.createElement('a');
+            link.href = 'data:image/png;base64,' + this.result.diff;
+            link.download = 'diff.png';
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        downloadReport() {
+            if (!this.result) return;
+
+            const blob = new Blob([JSON.stringify(this.result.metrics, null, 2)], { type: 'application/json' });
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = 'diff.json';
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            setTimeout(() => URL.revokeObjectURL(url), 100);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
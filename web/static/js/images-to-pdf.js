@@ -0,0 +1,99 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('imagesToPdf', () => ({
+        // State
+        files: [],
+        pageSize: 'a4',
+        orientation: 'auto',
+        margin: 24,
+        quality: 85,
+        building: false,
+        error: '',
+        resultBlob: null,
+
+        // Handle file selection
+        handleFileSelect(event) {
+            const files = Array.from(event.target.files);
+            if (files.length === 0) return;
+
+            const total = files.reduce((sum, file) => sum + file.size, 0);
+            if (total > 50 * 1024 * 1024) {
+                this.error = 'Files too large. Maximum total size is 50MB.';
+                return;
+            }
+
+            this.files = files;
+            this.error = '';
+            this.resultBlob = null;
+        },
+
+        // Move an image up or down in the page order
+        moveFile(index, delta) {
+            const target = index + delta;
+            if (target < 0 || target >= this.files.length) return;
+
+            const files = [...this.files];
+            [files[index], files[target]] = [files[target], files[index]];
+            this.files = files;
+        },
+
+        async build() {
+            if (this.files.length === 0) {
+                this.error = 'Please select at least one image';
+                return;
+            }
+
+            this.building = true;
+            this.error = '';
+            this.resultBlob = null;
+
+            try {
+                const formData = new FormData();
+                this.files.forEach(file => formData.append('images', file));
+                formData.append('page_size', this.pageSize);
+                formData.append('orientation', this.orientation);
+                formData.append('margin', this.margin);
+                formData.append('quality', this.quality);
+
+                const response = await fetch('/api/tools/pdf/from-images', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Conversion failed');
+                }
+
+                this.resultBlob = await response.blob();
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.building = false;
+            }
+        },
+
+        download() {
+            if (!this.resultBlob) return;
+
+            const url = URL.createObjectURL(this.resultBlob);
+            const a = document.createElement('a');
+            a.href = url;
+            a.download = 'images.pdf';
+            document.body.appendChild(a);
+            a.click();
+            document.body.removeChild(a);
+            setTimeout(() => URL.revokeObjectURL(url), 100);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
@@ -0,0 +1,102 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('pdfCompressor', () => ({
+        // State
+        file: null,
+        fileName: '',
+        fileSize: '',
+        preset: 'ebook',
+        dpi: '',
+        grayscale: false,
+        compressing: false,
+        error: '',
+        result: null,
+
+        // Handle file selection
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            if (file.type !== 'application/pdf') {
+                this.error = 'Please upload a valid PDF file.';
+                return;
+            }
+
+            if (file.size > 50 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 50MB.';
+                return;
+            }
+
+            this.file = file;
+            this.fileName = file.name;
+            this.fileSize = this.formatBytes(file.size);
+            this.error = '';
+            this.result = null;
+        },
+
+        async compress() {
+            if (!this.file) {
+                this.error = 'Please select a PDF file first';
+                return;
+            }
+
+            this.compressing = true;
+            this.error = '';
+            this.result = null;
+
+            try {
+                const formData = new FormData();
+                formData.append('pdf', this.file);
+                formData.append('preset', this.preset);
+                if (this.dpi) formData.append('dpi', this.dpi);
+                formData.append('grayscale', this.grayscale);
+
+                const response = await fetch('/api/tools/pdf/compress', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Compression failed');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+                this.result = data;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.compressing = false;
+            }
+        },
+
+        savings() {
+            if (!this.result || !this.result.original_size) return 0;
+            return Math.round((1 - this.result.compressed_size / this.result.original_size) * 100);
+        },
+
+        download() {
+            if (!this.result) return;
+
+            const link = document.createElement('a');
+            link.href = 'data:application/pdf;base64,' + this.result.pdf;
+            link.download = this.fileName.replace(/\.pdf$/i, '') + '_compressed.pdf';
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
@@ -7,7 +7,25 @@ document.addEventListener('alpine:init', () => {
         outputFormat: 'jpeg',
         dpi: 150,
         quality: 85,
+        sizeMode: 'dpi',
+        fitWidth: 1200,
+        fitHeight: '',
+        colorMode: 'color',
+        antiAlias: 4,
+        contactSheet: false,
+        columns: 4,
+        sheetUrl: null,
+        sheetBlob: null,
+        watermarkEnabled: false,
+        watermarkText: 'CONFIDENTIAL',
+        watermarkColor: '#808080',
+        watermarkOpacity: 30,
+        watermarkAngle: 30,
+        watermarkPosition: 'tile',
+        password: '',
+        needsPassword: false,
         converting: false,
+        downloadingAll: false,
         error: '',
         results: [],
 
@@ -31,6 +49,8 @@ document.addEventListener('alpine:init', () => {
             this.file = file;
             this.fileName = file.name;
             this.fileSize = this.formatBytes(file.size);
+            this.password = '';
+            this.needsPassword = false;
             this.error = '';
             this.results = [];
         },
@@ -45,13 +65,18 @@ document.addEventListener('alpine:init', () => {
             this.converting = true;
             this.error = '';
             this.results = [];
+            if (this.sheetUrl) URL.revokeObjectURL(this.sheetUrl);
+            this.sheetUrl = null;
+            this.sheetBlob = null;
 
             try {
-                const formData = new FormData();
-                formData.append('pdf', this.file);
-                formData.append('format', this.outputFormat);
-                formData.append('dpi', this.dpi);
-                formData.append('quality', this.quality);
+                if (this.contactSheet) {
+                    await this.renderContactSheet();
+                    return;
+                }
+
+                const formData = this.buildFormData();
+                formData.append('output', 'ndjson');
 
                 const response = await fetch('/api/tools/pdf/to-images', {
                     method: 'POST',
@@ -60,15 +85,30 @@ document.addEventListener('alpine:init', () => {
 
                 if (!response.ok) {
                     const errorText = await response.text();
+                    // 422 means the PDF is encrypted and needs a (different) password
+                    this.needsPassword = response.status === 422;
                     throw new Error(errorText || 'Conversion failed');
                 }
 
-                const data = await response.json();
+                // pages arrive one JSON object per line as they are rendered
+                const reader = response.body.getReader();
+                const decoder = new TextDecoder();
+                let buffer = '';
 
-                if (data.success && data.images) {
-                    this.results = data.images;
-                } else {
-                    throw new Error('Invalid response from server');
+                while (true) {
+                    const { done, value } = await reader.read();
+                    if (done) break;
+
+                    buffer += decoder.decode(value, { stream: true });
+                    const lines = buffer.split('\n');
+                    buffer = lines.pop();
+
+                    for (const line of lines) {
+                        if (!line.trim()) continue;
+                        const page = JSON.parse(line);
+                        if (page.error) throw new Error(page.error);
+                        this.results.push(page);
+                    }
                 }
 
             } catch (error) {
@@ -79,6 +119,104 @@ document.addEventListener('alpine:init', () => {
             }
         },
 
+        buildFormData() {
+            const formData = new FormData();
+            formData.append('pdf', this.file);
+            formData.append('format', this.outputFormat);
+            formData.append('quality', this.quality);
+            formData.append('color_mode', this.colorMode);
+            formData.append('text_aa', this.antiAlias);
+            formData.append('graphics_aa', this.antiAlias);
+            if (this.sizeMode === 'fit') {
+                if (this.fitWidth) formData.append('fit_width', this.fitWidth);
+                if (this.fitHeight) formData.append('fit_height', this.fitHeight);
+            } else {
+                formData.append('dpi', this.dpi);
+            }
+            if (this.password) formData.append('password', this.password);
+            if (this.watermarkEnabled && this.watermarkText.trim()) {
+                const tile = this.watermarkPosition === 'tile';
+                formData.append('watermark', JSON.stringify({
+                    text: this.watermarkText,
+                    color: this.watermarkColor,
+                    opacity: this.watermarkOpacity / 100,
+                    angle: this.watermarkAngle || 0,
+                    position: tile ? '' : this.watermarkPosition,
+                    tile: tile
+                }));
+            }
+            return formData;
+        },
+
+        async renderContactSheet() {
+            const formData = this.buildFormData();
+            formData.append('contact_sheet', 'true');
+            formData.append('columns', this.columns);
+
+            const response = await fetch('/api/tools/pdf/to-images', {
+                method: 'POST',
+                body: formData
+            });
+
+            if (!response.ok) {
+                const errorText = await response.text();
+                this.needsPassword = response.status === 422;
+                throw new Error(errorText || 'Conversion failed');
+            }
+
+            this.sheetBlob = await response.blob();
+            this.sheetUrl = URL.createObjectURL(this.sheetBlob);
+        },
+
+        downloadSheet() {
+            if (!this.sheetBlob) return;
+
+            const link = document.createElement('a');
+            link.href = this.sheetUrl;
+            const ext = this.outputFormat === 'jpeg' ? 'jpg' : this.outputFormat;
+            link.download = this.fileName.replace(/\.pdf$/i, '') + '_contact_sheet.' + ext;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        // Download every page as a ZIP archive
+        async downloadAll() {
+            this.downloadingAll = true;
+            this.error = '';
+
+            try {
+                const formData = this.buildFormData();
+                formData.append('output', 'zip');
+
+                const response = await fetch('/api/tools/pdf/to-images', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Download failed');
+                }
+
+                const blob = await response.blob();
+                const url = URL.createObjectURL(blob);
+                const link = document.createElement('a');
+                link.href = url;
+                link.download = this.fileName.replace(/\.pdf$/i, '') + '_pages.zip';
+                document.body.appendChild(link);
+                link.click();
+                document.body.removeChild(link);
+                setTimeout(() => URL.revokeObjectURL(url), 100);
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.downloadingAll = false;
+            }
+        },
+
         // Download a single image
         downloadImage(img) {
             const link = document.createElement('a');
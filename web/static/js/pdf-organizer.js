@@ -0,0 +1,133 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('pdfOrganizer', () => ({
+        // State
+        mode: 'merge',
+        files: [],
+        ranges: '',
+        pages: '',
+        angle: 90,
+        order: '',
+        processing: false,
+        error: '',
+        resultBlob: null,
+        resultName: '',
+
+        reset() {
+            this.files = [];
+            this.error = '';
+            this.resultBlob = null;
+        },
+
+        // Handle file selection
+        handleFileSelect(event) {
+            const files = Array.from(event.target.files);
+            if (files.length === 0) return;
+
+            if (files.some(file => file.type !== 'application/pdf')) {
+                this.error = 'Please upload valid PDF files only.';
+                return;
+            }
+
+            const total = files.reduce((sum, file) => sum + file.size, 0);
+            if (total > 50 * 1024 * 1024) {
+                this.error = 'Files too large. Maximum total size is 50MB.';
+                return;
+            }
+
+            this.files = files;
+            this.error = '';
+            this.resultBlob = null;
+        },
+
+        // Move a file up or down in the merge order
+        moveFile(index, delta) {
+            const target = index + delta;
+            if (target < 0 || target >= this.files.length) return;
+
+            const files = [...this.files];
+            [files[index], files[target]] = [files[target], files[index]];
+            this.files = files;
+        },
+
+        async process() {
+            if (this.files.length === 0) {
+                this.error = 'Please select a PDF file first';
+                return;
+            }
+            if (this.mode === 'merge' && this.files.length < 2) {
+                this.error = 'Select at least two PDFs to merge';
+                return;
+            }
+
+            this.processing = true;
+            this.error = '';
+            this.resultBlob = null;
+
+            try {
+                const formData = new FormData();
+                let endpoint = '/api/tools/pdf/' + this.mode;
+
+                if (this.mode === 'merge') {
+                    // files are sent in the order shown, so no explicit order is needed
+                    this.files.forEach(file => formData.append('pdfs', file));
+                } else {
+                    formData.append('pdf', this.files[0]);
+                }
+
+                if (this.mode === 'split') formData.append('ranges', this.ranges);
+                if (this.mode === 'rotate') {
+                    formData.append('pages', this.pages);
+                    formData.append('angle', this.angle);
+                }
+                if (this.mode === 'reorder') formData.append('order', this.order);
+
+                const response = await fetch(endpoint, {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Processing failed');
+                }
+
+                this.resultBlob = await response.blob();
+
+                const disposition = response.headers.get('Content-Disposition');
+                this.resultName = 'document.pdf';
+                if (disposition) {
+                    const matches = /filename="?([^"]+)"?/.exec(disposition);
+                    if (matches) this.resultName = matches[1];
+                }
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.processing = false;
+            }
+        },
+
+        download() {
+            if (!this.resultBlob) return;
+
+            const url = URL.createObjectURL(this.resultBlob);
+            const a = document.createElement('a');
+            a.href = url;
+            a.download = this.resultName;
+            document.body.appendChild(a);
+            a.click();
+            document.body.removeChild(a);
+            setTimeout(() => URL.revokeObjectURL(url), 100);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
@@ -0,0 +1,130 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('pdfText', () => ({
+        // State
+        file: null,
+        fileName: '',
+        fileSize: '',
+        output: 'text',
+        firstPage: '',
+        lastPage: '',
+        extracting: false,
+        error: '',
+        result: null,
+
+        // Handle file selection
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            if (file.type !== 'application/pdf') {
+                this.error = 'Please upload a valid PDF file.';
+                return;
+            }
+
+            if (file.size > 50 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 50MB.';
+                return;
+            }
+
+            this.file = file;
+            this.fileName = file.name;
+            this.fileSize = this.formatBytes(file.size);
+            this.error = '';
+            this.result = null;
+        },
+
+        formData(output) {
+            const formData = new FormData();
+            formData.append('pdf', this.file);
+            formData.append('output', output);
+            if (this.firstPage) formData.append('first_page', this.firstPage);
+            if (this.lastPage) formData.append('last_page', this.lastPage);
+            return formData;
+        },
+
+        async extract() {
+            if (!this.file) {
+                this.error = 'Please select a PDF file first';
+                return;
+            }
+
+            this.extracting = true;
+            this.error = '';
+            this.result = null;
+
+            try {
+                const response = await fetch('/api/tools/pdf/to-text', {
+                    method: 'POST',
+                    body: this.formData(this.output)
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Extraction failed');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+                this.result = data;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.extracting = false;
+            }
+        },
+
+        display() {
+            if (!this.result) return '';
+            if (this.result.pages) return JSON.stringify(this.result.pages, null, 2);
+            return this.result.text;
+        },
+
+        async copy() {
+            try {
+                await navigator.clipboard.writeText(this.display());
+            } catch (error) {
+                this.error = 'Failed to copy to clipboard';
+            }
+        },
+
+        async download() {
+            try {
+                const response = await fetch('/api/tools/pdf/to-text', {
+                    method: 'POST',
+                    body: this.formData('download')
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Download failed');
+                }
+
+                const blob = await response.blob();
+                const url = URL.createObjectURL(blob);
+                const a = document.createElement('a');
+                a.href = url;
+                a.download = this.fileName.replace(/\.pdf$/i, '') + '.txt';
+                document.body.appendChild(a);
+                a.click();
+                document.body.removeChild(a);
+                setTimeout(() => URL.revokeObjectURL(url), 100);
+
+            } catch (error) {
+                this.error = error.message;
+            }
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
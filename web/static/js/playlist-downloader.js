@@ -0,0 +1,230 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('playlistDownloader', () => ({
+        // State
+        url: '',
+        loading: false,
+        starting: false,
+        cancelling: false,
+        error: '',
+        playlist: null,
+        maxItems: 50,
+        selected: [],
+        range: '',
+        quality: '720p',
+        audioExt: 'mp3',
+        job: null,
+        pollTimer: null,
+
+        async listEntries() {
+            if (!this.url.trim()) {
+                this.error = 'Please enter a playlist URL';
+                return;
+            }
+
+            this.loading = true;
+            this.error = '';
+            this.playlist = null;
+            this.job = null;
+
+            try {
+                const formData = new FormData();
+                formData.append('url', this.url.trim());
+
+                const response = await fetch('/api/tools/video/playlist/info', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to list entries');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+
+                this.playlist = data.playlist;
+                this.maxItems = data.max_items;
+                this.selected = this.playlist.entries.slice(0, this.maxItems).map(e => e.index);
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.loading = false;
+            }
+        },
+
+        // applyRange selects entries such as "1-10, 15"
+        applyRange() {
+            const count = this.playlist.entries.length;
+            const picked = new Set();
+
+            for (const part of this.range.split(',')) {
+                const field = part.trim();
+                if (!field) continue;
+
+                const [from, to] = field.split('-').map(v => parseInt(v, 10));
+                const end = isNaN(to) ? from : to;
+                if (isNaN(from) || from < 1 || end < from || end > count) {
+                    this.error = `"${field}" is outside entries 1-${count}`;
+                    return;
+                }
+                for (let i = from; i <= end; i++) picked.add(i);
+            }
+
+            this.error = '';
+            this.selected = [...picked].sort((a, b) => a - b);
+        },
+
+        selectedEntries() {
+            return (this.playlist?.entries || []).filter(e => this.selected.includes(e.index));
+        },
+
+        selectedDuration() {
+            return this.selectedEntries().reduce((sum, e) => sum + (e.duration || 0), 0);
+        },
+
+        selectedSize() {
+            return this.selectedEntries().reduce((sum, e) => sum + (e.filesize || 0), 0);
+        },
+
+        async startDownload() {
+            if (this.selected.length > this.maxItems) {
+                this.error = `Please select at most ${this.maxItems} entries`;
+                return;
+            }
+
+            this.starting = true;
+            this.error = '';
+
+            try {
+                const formData = new FormData();
+                formData.append('url', this.url.trim());
+                formData.append('items', this.selected.join(','));
+                formData.append('quality', this.quality);
+                formData.append('audio_ext', this.audioExt);
+
+                const response = await fetch('/api/tools/video/playlist/jobs', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to start the download');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+
+                this.job = data.job;
+                this.poll();
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.starting = false;
+            }
+        },
+
+        poll() {
+            clearTimeout(this.pollTimer);
+            if (!this.job || this.job.status !== 'running') return;
+
+            this.pollTimer = setTimeout(async () => {
+                try {
+                    const response = await fetch('/api/tools/video/playlist/jobs/' + this.job.id);
+                    if (!response.ok) {
+                        const errorText = await response.text();
+                        throw new Error(errorText || 'Failed to check progress');
+                    }
+                    const data = await response.json();
+                    this.job = data.job;
+                } catch (error) {
+                    console.error(error);
+                    this.error = error.message;
+                    return;
+                }
+                this.poll();
+            }, 2000);
+        },
+
+        async cancel() {
+            if (!this.job) return;
+
+            this.cancelling = true;
+            try {
+                const response = await fetch('/api/tools/video/playlist/jobs/' + this.job.id, {
+                    method: 'DELETE'
+                });
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to stop the download');
+                }
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.cancelling = false;
+            }
+        },
+
+        reset() {
+            clearTimeout(this.pollTimer);
+            this.job = null;
+            this.playlist = null;
+            this.selected = [];
+            this.range = '';
+        },
+
+        doneCount() {
+            return (this.job?.items || []).filter(i => i.status === 'done').length;
+        },
+
+        statusLabel(item) {
+            const labels = {
+                pending: 'Waiting',
+                downloading: 'Downloading...',
+                failed: 'Failed',
+                skipped: 'Skipped'
+            };
+            return labels[item.status] + (item.error ? ': ' + item.error.split('\n')[0] : '');
+        },
+
+        fileUrl(item) {
+            return `/api/tools/video/playlist/jobs/${this.job.id}/files/${item.index}`;
+        },
+
+        archiveUrl() {
+            return this.job ? `/api/tools/video/playlist/jobs/${this.job.id}/archive` : '';
+        },
+
+        formatDuration(seconds) {
+            if (!seconds) return 'Unknown length';
+            seconds = Math.round(seconds);
+            const hours = Math.floor(seconds / 3600);
+            const minutes = Math.floor((seconds % 3600) / 60);
+            const secs = seconds % 60;
+
+            if (hours > 0) {
+                return `${hours}:${minutes.toString().padStart(2, '0')}:${secs.toString().padStart(2, '0')}`;
+            }
+            return `${minutes}:${secs.toString().padStart(2, '0')}`;
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
@@ -17,6 +17,7 @@ document.addEventListener('alpine:init', () => {
         errorCorrection: 1, // Medium
         foregroundColor: '#000000',
         backgroundColor: '#ffffff',
+        logoFile: null,
         generating: false,
         error: '',
         qrCode: null,
@@ -38,6 +39,9 @@ document.addEventListener('alpine:init', () => {
                 formData.append('error_correction', this.errorCorrection);
                 formData.append('foreground_color', this.foregroundColor);
                 formData.append('background_color', this.backgroundColor);
+                if (this.logoFile) {
+                    formData.append('logo', this.logoFile);
+                }
 
                 // Add type-specific data
                 if (this.qrType === 'text') {
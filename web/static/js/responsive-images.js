@@ -0,0 +1,108 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('responsiveImages', () => ({
+        // State
+        file: null,
+        fileName: '',
+        fileSize: '',
+        widths: '320, 640, 1024, 1920',
+        formats: ['webp', 'jpeg'],
+        quality: 80,
+        sizes: '100vw',
+        alt: '',
+        generating: false,
+        error: '',
+        result: null,
+
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            // Validate file size (10MB limit)
+            if (file.size > 10 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 10MB.';
+                return;
+            }
+
+            this.file = file;
+            this.fileName = file.name;
+            this.fileSize = this.formatBytes(file.size);
+            this.error = '';
+            this.result = null;
+        },
+
+        async generate() {
+            if (!this.file) {
+                this.error = 'Please select an image first';
+                return;
+            }
+            if (this.formats.length === 0) {
+                this.error = 'Choose at least one format';
+                return;
+            }
+
+            this.generating = true;
+            this.error = '';
+            this.result = null;
+
+            try {
+                const formData = new FormData();
+                formData.append('image', this.file);
+                formData.append('widths', this.widths);
+                this.formats.forEach(f => formData.append('formats', f));
+                formData.append('quality', this.quality);
+                formData.append('sizes', this.sizes);
+                formData.append('alt', this.alt);
+
+                const response = await fetch('/api/tools/image/responsive', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Failed to generate images');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+                this.result = data;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.generating = false;
+            }
+        },
+
+        async copySnippet() {
+            try {
+                await navigator.clipboard.writeText(this.result.manifest.html);
+            } catch (error) {
+                this.error = 'Failed to copy to clipboard';
+            }
+        },
+
+        download() {
+            if (!this.result) return;
+
+            const link = document.createElement('a');
+            link.href = 'data:application/zip;base64,' + this.result.archive;
+            link.download = this.fileName.replace(/\.[^/.]+$/, '') + '_responsive.zip';
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
@@ -0,0 +1,150 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('svgTools', () => ({
+        // State
+        file: null,
+        markup: '',
+        mode: 'optimize',
+        precision: 3,
+        keepTitle: true,
+        format: 'png',
+        quality: 90,
+        widths: '',
+        processing: false,
+        error: '',
+        result: null,
+        rasters: null,
+
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            // Validate file size (10MB limit)
+            if (file.size > 10 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 10MB.';
+                return;
+            }
+
+            this.file = file;
+            this.error = '';
+            this.result = null;
+            this.rasters = null;
+        },
+
+        async run() {
+            if (!this.file && !this.markup.trim()) {
+                this.error = 'Please select an SVG or paste its markup first';
+                return;
+            }
+
+            this.processing = true;
+            this.error = '';
+            this.result = null;
+            this.rasters = null;
+
+            try {
+                const formData = new FormData();
+                if (this.file) {
+                    formData.append('svg', this.file);
+                } else {
+                    formData.append('markup', this.markup);
+                }
+
+                let url = '/api/tools/svg/optimize';
+                if (this.mode === 'rasterize') {
+                    url = '/api/tools/svg/rasterize';
+                    formData.append('format', this.format);
+                    formData.append('quality', this.quality);
+                    formData.append('widths', this.widths);
+                } else {
+                    formData.append('mode', this.mode);
+                    formData.append('precision', this.precision);
+                    formData.append('keep_title', this.keepTitle);
+                }
+
+                const response = await fetch(url, {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Processing failed');
+                }
+
+                const data = await response.json();
+                if (!data.success) {
+                    throw new Error('Invalid response from server');
+                }
+
+                if (this.mode === 'rasterize') {
+                    this.rasters = data;
+                } else {
+                    this.result = data;
+                }
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.processing = false;
+            }
+        },
+
+        savings() {
+            if (!this.result || !this.result.original_size) return 0;
+            return Math.round((1 - this.result.size / this.result.original_size) * 1000) / 10;
+        },
+
+        async copy() {
+            try {
+                await navigator.clipboard.writeText(this.result.svg);
+            } catch (error) {
+                this.error = 'Failed to copy to clipboard';
+            }
+        },
+
+        baseName() {
+            return this.file ? this.file.name.replace(/\.[^/.]+$/, '') : 'image';
+        },
+
+        downloadSVG() {
+            if (!this.result) return;
+
+            const blob = new Blob([this.result.svg], { type: 'image/svg+xml' });
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = this.baseName() + (this.mode === 'sanitize' ? '_clean.svg' : '.min.svg');
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            setTimeout(() => URL.revokeObjectURL(url), 100);
+        },
+
+        downloadRasters() {
+            if (!this.rasters) return;
+
+            const link = document.createElement('a');
+            if (this.rasters.files.length === 1) {
+                const type = this.format === 'webp' ? 'image/webp' : 'image/png';
+                link.href = `data:${type};base64,` + this.rasters.preview;
+                link.download = this.rasters.files[0].name;
+            } else {
+                link.href = 'data:application/zip;base64,' + this.rasters.archive;
+                link.download = this.baseName() + '_images.zip';
+            }
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
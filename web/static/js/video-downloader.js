@@ -7,6 +7,21 @@ function videoDownloader() {
         error: '',
         videoInfo: null,
         selectedQuality: '720p',
+        chapter: '',
+        clipStart: '',
+        clipEnd: '',
+        forceKeyframes: false,
+        formatMode: 'preset',
+        codec: '',
+        videoFormat: '',
+        audioFormat: '',
+        audioExt: 'mp3',
+        audioBitrate: '',
+        embedMetadata: true,
+        embedThumbnail: true,
+        subtitleIndex: 0,
+        subtitleFormat: 'srt',
+        fetchingSubtitles: false,
 
         checkPlatform() {
             this.isYouTube = this.url.includes('youtube.com') || this.url.includes('youtu.be');
@@ -21,6 +36,13 @@ function videoDownloader() {
             this.loading = true;
             this.error = '';
             this.videoInfo = null;
+            this.chapter = '';
+            this.clipStart = '';
+            this.clipEnd = '';
+            this.formatMode = 'preset';
+            this.videoFormat = '';
+            this.audioFormat = '';
+            this.subtitleIndex = 0;
 
             try {
                 const formData = new FormData();
@@ -51,8 +73,36 @@ function videoDownloader() {
             try {
                 const formData = new FormData();
                 formData.append('url', this.url);
-                formData.append('quality', this.selectedQuality);
                 formData.append('format', 'mp4');
+                if (this.formatMode === 'exact') {
+                    if (!this.videoFormat && !this.audioFormat) {
+                        throw new Error('Please choose a video or audio format');
+                    }
+                    formData.append('video_format', this.videoFormat);
+                    if (this.selectedFormat(this.videoFormat)?.kind !== 'muxed') {
+                        formData.append('audio_format', this.audioFormat);
+                    }
+                } else {
+                    formData.append('quality', this.selectedQuality);
+                    formData.append('codec', this.codec);
+                    if (this.selectedQuality === 'audio') {
+                        formData.append('audio_ext', this.audioExt);
+                        if (!this.isLossless()) {
+                            formData.append('audio_bitrate', this.audioBitrate);
+                        }
+                        formData.append('embed_metadata', this.embedMetadata);
+                        formData.append('embed_thumbnail', this.embedThumbnail);
+                    }
+                }
+                if (this.chapter !== '') {
+                    formData.append('chapter', this.chapter);
+                } else {
+                    formData.append('start', this.clipStart.trim());
+                    formData.append('end', this.clipEnd.trim());
+                }
+                if (this.isClip()) {
+                    formData.append('force_keyframes', this.forceKeyframes);
+                }
 
                 const response = await fetch('/api/tools/video/download', {
                     method: 'POST',
@@ -89,6 +139,91 @@ function videoDownloader() {
             }
         },
 
+        async downloadSubtitles() {
+            const lang = this.videoInfo?.subtitle_languages?.[this.subtitleIndex];
+            if (!lang) return;
+
+            this.fetchingSubtitles = true;
+            this.error = '';
+
+            try {
+                const formData = new FormData();
+                formData.append('url', this.url);
+                formData.append('lang', lang.code);
+                formData.append('auto', lang.auto);
+                formData.append('format', this.subtitleFormat);
+
+                const response = await fetch('/api/tools/video/subtitles', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Subtitle download failed');
+                }
+
+                const blob = await response.blob();
+                const url = URL.createObjectURL(blob);
+                const a = document.createElement('a');
+                a.href = url;
+                a.download = `subtitles.${lang.code}.${this.subtitleFormat}`;
+                document.body.appendChild(a);
+                a.click();
+                document.body.removeChild(a);
+                URL.revokeObjectURL(url);
+
+            } catch (error) {
+                this.error = error.message;
+            } finally {
+                this.fetchingSubtitles = false;
+            }
+        },
+
+        // formats with video, best first
+        videoFormats() {
+            return (this.videoInfo?.formats || []).filter(f => f.kind !== 'audio').reverse();
+        },
+
+        audioFormats() {
+            return (this.videoInfo?.formats || []).filter(f => f.kind === 'audio').reverse();
+        },
+
+        selectedFormat(id) {
+            return (this.videoInfo?.formats || []).find(f => f.format_id === id);
+        },
+
+        formatLabel(f) {
+            const parts = [];
+            if (f.kind === 'audio') {
+                parts.push(f.acodec);
+                if (f.tbr) parts.push(Math.round(f.tbr) + ' kbps');
+            } else {
+                parts.push(f.height ? f.height + 'p' : f.resolution);
+                if (f.fps) parts.push(Math.round(f.fps) + 'fps');
+                parts.push(f.vcodec);
+                if (f.kind === 'muxed') parts.push('video + audio');
+            }
+            parts.push(f.ext);
+            if (f.filesize) parts.push(this.formatBytes(f.filesize));
+            if (f.format_note) parts.push(f.format_note);
+            return parts.join(' · ');
+        },
+
+        exactSize() {
+            const video = this.selectedFormat(this.videoFormat);
+            const audio = video?.kind === 'muxed' ? null : this.selectedFormat(this.audioFormat);
+            return (video?.filesize || 0) + (audio?.filesize || 0);
+        },
+
+        isLossless() {
+            return this.audioExt === 'flac' || this.audioExt === 'wav';
+        },
+
+        isClip() {
+            return this.chapter !== '' || this.clipStart.trim() !== '' || this.clipEnd.trim() !== '';
+        },
+
         formatDuration(seconds) {
             if (!seconds) return 'Unknown';
             const hours = Math.floor(seconds / 3600);
@@ -0,0 +1,112 @@
+document.addEventListener('alpine:init', () => {
+    Alpine.data('videoToGif', () => ({
+        // State
+        source: 'upload',
+        file: null,
+        url: '',
+        start: '',
+        end: '',
+        format: 'gif',
+        width: 480,
+        fps: 12,
+        loop: '0',
+        quality: 75,
+        converting: false,
+        error: '',
+        resultUrl: null,
+        resultSize: 0,
+
+        handleFileSelect(event) {
+            const file = event.target.files[0];
+            if (!file) return;
+
+            // Validate file size (50MB limit)
+            if (file.size > 50 * 1024 * 1024) {
+                this.error = 'File too large. Maximum size is 50MB.';
+                return;
+            }
+
+            this.file = file;
+            this.error = '';
+        },
+
+        async convert() {
+            if (this.source === 'upload' && !this.file) {
+                this.error = 'Please select a video first';
+                return;
+            }
+            if (this.source === 'url' && !this.url.trim()) {
+                this.error = 'Please enter a video URL';
+                return;
+            }
+
+            this.converting = true;
+            this.error = '';
+            this.clearResult();
+
+            try {
+                const formData = new FormData();
+                if (this.source === 'upload') {
+                    formData.append('video', this.file);
+                } else {
+                    formData.append('url', this.url.trim());
+                }
+                formData.append('start', this.start);
+                formData.append('end', this.end);
+                formData.append('format', this.format);
+                formData.append('width', this.width);
+                formData.append('fps', this.fps);
+                formData.append('loop', this.loop);
+                formData.append('quality', this.quality);
+
+                const response = await fetch('/api/tools/video/gif', {
+                    method: 'POST',
+                    body: formData
+                });
+
+                if (!response.ok) {
+                    const errorText = await response.text();
+                    throw new Error(errorText || 'Conversion failed');
+                }
+
+                const blob = await response.blob();
+                this.resultUrl = URL.createObjectURL(blob);
+                this.resultSize = blob.size;
+
+            } catch (error) {
+                console.error(error);
+                this.error = error.message;
+            } finally {
+                this.converting = false;
+            }
+        },
+
+        clearResult() {
+            if (this.resultUrl) {
+                URL.revokeObjectURL(this.resultUrl);
+            }
+            this.resultUrl = null;
+            this.resultSize = 0;
+        },
+
+        download() {
+            if (!this.resultUrl) return;
+
+            const link = document.createElement('a');
+            link.href = this.resultUrl;
+            link.download = 'animation.' + this.format;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+        },
+
+        // Format bytes to human-readable string
+        formatBytes(bytes) {
+            if (bytes === 0) return '0 Bytes';
+            const k = 1024;
+            const sizes = ['Bytes', 'KB', 'MB', 'GB'];
+            const i = Math.floor(Math.log(bytes) / Math.log(k));
+            return Math.round(bytes / Math.pow(k, i) * 100) / 100 + ' ' + sizes[i];
+        }
+    }));
+});
@@ -41,7 +41,7 @@ func HomePage() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"container\"><!-- Hero Section --><section class=\"hero-section\"><div class=\"hero-content\"><div class=\"hero-emoji\">🛠️</div><h1 class=\"hero-title\">NanoTools</h1><p class=\"hero-subtitle\">Privacy-first web utilities for everyday tasks</p><div class=\"hero-badges\"><span class=\"badge\"><span class=\"badge-icon\">🔒</span> Privacy First</span> <span class=\"badge\"><span class=\"badge-icon\">⚡</span> Lightning Fast</span> <span class=\"badge\"><span class=\"badge-icon\">🚫</span> No Tracking</span> <span class=\"badge\"><span class=\"badge-icon\">🎨</span> Open Source</span></div></div></section><!-- Media Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">🎬</div><div><h2 class=\"category-title\">Media Tools</h2><p class=\"category-description\">Work with video and animated content</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/video-to-gif\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🎞️</div><h3 class=\"tool-card-title\">Video to GIF</h3><p class=\"tool-card-description\">Convert video clips to optimized, high-quality GIFs</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Convert</span> <span class=\"tool-tag\">Optimize</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/video-downloader\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📹</div><h3 class=\"tool-card-title\">Video Downloader</h3><p class=\"tool-card-description\">Download videos from 1000+ sites for offline viewing</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">YouTube</span> <span class=\"tool-tag\">Educational</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- QR & Sharing Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">📱</div><div><h2 class=\"category-title\">QR & Sharing</h2><p class=\"category-description\">Generate scannable codes and shareable content</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/qr-code\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">⬛</div><h3 class=\"tool-card-title\">QR Code Generator</h3><p class=\"tool-card-description\">Create QR codes for URLs, Wi-Fi, contacts, and more</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">QR</span> <span class=\"tool-tag\">Wi-Fi</span> <span class=\"tool-tag\">vCard</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Image Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">🖼️</div><div><h2 class=\"category-title\">Image Tools</h2><p class=\"category-description\">Convert, compress, and optimize images</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/image-converter\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🎨</div><h3 class=\"tool-card-title\">Image Converter</h3><p class=\"tool-card-description\">Convert between JPEG, PNG, and WebP with quality control</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Convert</span> <span class=\"tool-tag\">Modern</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/image-compressor\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📦</div><h3 class=\"tool-card-title\">Image Compressor</h3><p class=\"tool-card-description\">Reduce image file sizes without sacrificing quality</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Compress</span> <span class=\"tool-tag\">Optimize</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Document Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">📄</div><div><h2 class=\"category-title\">Document Tools</h2><p class=\"category-description\">Process and convert PDFs</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/pdf-to-images\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📸</div><h3 class=\"tool-card-title\">PDF to Images</h3><p class=\"tool-card-description\">Extract pages from PDFs as high-quality images</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Extract</span> <span class=\"tool-tag\">Convert</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Text Tools Category --><section id=\"tools\" class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">📝</div><div><h2 class=\"category-title\">Text Tools</h2><p class=\"category-description\">Format, encode, and transform text instantly</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/json-formatter\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📋</div><h3 class=\"tool-card-title\">JSON Formatter</h3><p class=\"tool-card-description\">Format and validate JSON with syntax highlighting and live feedback</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Format</span> <span class=\"tool-tag\">Validate</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/base64\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🔐</div><h3 class=\"tool-card-title\">Base64 Encoder</h3><p class=\"tool-card-description\">Encode and decode Base64 strings for data URIs and APIs</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Encode</span> <span class=\"tool-tag\">Decode</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/uuid\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🎲</div><h3 class=\"tool-card-title\">UUID Generator</h3><p class=\"tool-card-description\">Generate random UUIDs (v4) for databases and unique identifiers</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Generate</span> <span class=\"tool-tag\">Bulk</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/slugify\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🔗</div><h3 class=\"tool-card-title\">Slugify</h3><p class=\"tool-card-description\">Convert text to URL-friendly slugs with smart transliteration</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">URLs</span> <span class=\"tool-tag\">Clean</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Stats Section --><section class=\"stats-section\"><h2 style=\"font-size: 2rem; margin-bottom: 0.5rem;\">Trusted by Privacy-Conscious Users</h2><p style=\"opacity: 0.9; margin-bottom: 2rem;\">All processing happens on your server. Zero tracking. Complete privacy.</p><div class=\"stats-grid\"><div class=\"stat-item\"><span class=\"stat-number\">10+</span> <span class=\"stat-label\">Powerful Tools</span></div><div class=\"stat-item\"><span class=\"stat-number\">100%</span> <span class=\"stat-label\">Private</span></div><div class=\"stat-item\"><span class=\"stat-number\">0</span> <span class=\"stat-label\">Tracking Scripts</span></div><div class=\"stat-item\"><span class=\"stat-number\">∞</span> <span class=\"stat-label\">Free Forever</span></div></div></section><!-- Footer CTA --><section class=\"footer-cta\"><div class=\"footer-cta-title\">Ready to take control?</div><p class=\"footer-cta-text\">Self-host NanoTools and enjoy privacy-first utilities on your own server.<br>No data ever leaves your infrastructure.</p><a href=\"https://github.com/tmunongo/nanotools\" class=\"cta-button\"><span>⭐</span> View on GitHub</a></section><!-- Footer --><footer class=\"site-footer\" style=\"margin-top: 4rem;\"><p>Built with ❤️ for privacy-conscious users</p><p>All processing happens on your server • No tracking • Open source</p></footer></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"container\"><!-- Hero Section --><section class=\"hero-section\"><div class=\"hero-content\"><div class=\"hero-emoji\">🛠️</div><h1 class=\"hero-title\">NanoTools</h1><p class=\"hero-subtitle\">Privacy-first web utilities for everyday tasks</p><div class=\"hero-badges\"><span class=\"badge\"><span class=\"badge-icon\">🔒</span> Privacy First</span> <span class=\"badge\"><span class=\"badge-icon\">⚡</span> Lightning Fast</span> <span class=\"badge\"><span class=\"badge-icon\">🚫</span> No Tracking</span> <span class=\"badge\"><span class=\"badge-icon\">🎨</span> Open Source</span></div></div></section><!-- Media Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">🎬</div><div><h2 class=\"category-title\">Media Tools</h2><p class=\"category-description\">Work with video and animated content</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/video-to-gif\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🎞️</div><h3 class=\"tool-card-title\">Video to GIF</h3><p class=\"tool-card-description\">Convert video clips to optimized, high-quality GIFs</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Convert</span> <span class=\"tool-tag\">Optimize</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/video-downloader\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📹</div><h3 class=\"tool-card-title\">Video Downloader</h3><p class=\"tool-card-description\">Download videos from 1000+ sites for offline viewing</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">YouTube</span> <span class=\"tool-tag\">Educational</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/playlist-downloader\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📚</div><h3 class=\"tool-card-title\">Playlist Downloader</h3><p class=\"tool-card-description\">Archive selected entries from a playlist or channel as a ZIP</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Batch</span> <span class=\"tool-tag\">Archive</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- QR & Sharing Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">📱</div><div><h2 class=\"category-title\">QR & Sharing</h2><p class=\"category-description\">Generate scannable codes and shareable content</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/qr-code\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">⬛</div><h3 class=\"tool-card-title\">QR Code Generator</h3><p class=\"tool-card-description\">Create QR codes for URLs, Wi-Fi, contacts, and more</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">QR</span> <span class=\"tool-tag\">Wi-Fi</span> <span class=\"tool-tag\">vCard</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Image Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">🖼️</div><div><h2 class=\"category-title\">Image Tools</h2><p class=\"category-description\">Convert, compress, and optimize images</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/image-converter\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🎨</div><h3 class=\"tool-card-title\">Image Converter</h3><p class=\"tool-card-description\">Convert between JPEG, PNG, and WebP with quality control</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Convert</span> <span class=\"tool-tag\">Modern</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/image-compressor\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📦</div><h3 class=\"tool-card-title\">Image Compressor</h3><p class=\"tool-card-description\">Reduce image file sizes without sacrificing quality</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Compress</span> <span class=\"tool-tag\">Optimize</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/favicon-generator\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">⭐</div><h3 class=\"tool-card-title\">Favicon Generator</h3><p class=\"tool-card-description\">Create favicon.ico, app icons and a web manifest from one image or SVG</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Icons</span> <span class=\"tool-tag\">PWA</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/responsive-images\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📐</div><h3 class=\"tool-card-title\">Responsive Images</h3><p class=\"tool-card-description\">Generate srcset widths in WebP and JPEG with picture markup and a blurred placeholder</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Web</span> <span class=\"tool-tag\">Performance</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/image-diff\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🔍</div><h3 class=\"tool-card-title\">Image Diff</h3><p class=\"tool-card-description\">Highlight what changed between two screenshots with PSNR and SSIM scores</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Images</span> <span class=\"tool-tag\">Testing</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/color-palette\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🎨</div><h3 class=\"tool-card-title\">Color Palette</h3><p class=\"tool-card-description\">Extract dominant colours as hex, RGB and HSL with CSS and Tailwind snippets</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Images</span> <span class=\"tool-tag\">Design</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/svg-tools\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">✒️</div><h3 class=\"tool-card-title\">SVG Tools</h3><p class=\"tool-card-description\">Minify and sanitize SVGs or render them to PNG and WebP at any size</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Images</span> <span class=\"tool-tag\">Vector</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Document Tools Category --><section class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">📄</div><div><h2 class=\"category-title\">Document Tools</h2><p class=\"category-description\">Process and convert PDFs</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/pdf-to-images\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📸</div><h3 class=\"tool-card-title\">PDF to Images</h3><p class=\"tool-card-description\">Extract pages from PDFs as high-quality images</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Extract</span> <span class=\"tool-tag\">Convert</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/pdf-organizer\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🗂️</div><h3 class=\"tool-card-title\">PDF Organizer</h3><p class=\"tool-card-description\">Merge, split, rotate, reorder and delete PDF pages</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Merge</span> <span class=\"tool-tag\">Split</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/pdf-compressor\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🗜️</div><h3 class=\"tool-card-title\">PDF Compressor</h3><p class=\"tool-card-description\">Shrink oversized PDFs with quality presets</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Compress</span> <span class=\"tool-tag\">Optimize</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/images-to-pdf\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📑</div><h3 class=\"tool-card-title\">Images to PDF</h3><p class=\"tool-card-description\">Combine JPEG, PNG and WebP images into one PDF</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Combine</span> <span class=\"tool-tag\">Convert</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/pdf-to-text\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🔤</div><h3 class=\"tool-card-title\">PDF to Text</h3><p class=\"tool-card-description\">Extract text from PDFs as plain text, Markdown or JSON</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Extract</span> <span class=\"tool-tag\">Text</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Text Tools Category --><section id=\"tools\" class=\"category-section\"><div class=\"category-header\"><div class=\"category-icon\">📝</div><div><h2 class=\"category-title\">Text Tools</h2><p class=\"category-description\">Format, encode, and transform text instantly</p></div></div><div class=\"tools-category-grid\"><a href=\"/tools/json-formatter\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">📋</div><h3 class=\"tool-card-title\">JSON Formatter</h3><p class=\"tool-card-description\">Format and validate JSON with syntax highlighting and live feedback</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Format</span> <span class=\"tool-tag\">Validate</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/base64\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🔐</div><h3 class=\"tool-card-title\">Base64 Encoder</h3><p class=\"tool-card-description\">Encode and decode Base64 strings for data URIs and APIs</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Encode</span> <span class=\"tool-tag\">Decode</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/uuid\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🎲</div><h3 class=\"tool-card-title\">UUID Generator</h3><p class=\"tool-card-description\">Generate random UUIDs (v4) for databases and unique identifiers</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">Generate</span> <span class=\"tool-tag\">Bulk</span></div><span class=\"tool-card-link\">Try it →</span></a> <a href=\"/tools/slugify\" class=\"tool-card-enhanced\"><div class=\"tool-card-icon\">🔗</div><h3 class=\"tool-card-title\">Slugify</h3><p class=\"tool-card-description\">Convert text to URL-friendly slugs with smart transliteration</p><div class=\"tool-card-tags\"><span class=\"tool-tag\">URLs</span> <span class=\"tool-tag\">Clean</span></div><span class=\"tool-card-link\">Try it →</span></a></div></section><!-- Stats Section --><section class=\"stats-section\"><h2 style=\"font-size: 2rem; margin-bottom: 0.5rem;\">Trusted by Privacy-Conscious Users</h2><p style=\"opacity: 0.9; margin-bottom: 2rem;\">All processing happens on your server. Zero tracking. Complete privacy.</p><div class=\"stats-grid\"><div class=\"stat-item\"><span class=\"stat-number\">10+</span> <span class=\"stat-label\">Powerful Tools</span></div><div class=\"stat-item\"><span class=\"stat-number\">100%</span> <span class=\"stat-label\">Private</span></div><div class=\"stat-item\"><span class=\"stat-number\">0</span> <span class=\"stat-label\">Tracking Scripts</span></div><div class=\"stat-item\"><span class=\"stat-number\">∞</span> <span class=\"stat-label\">Free Forever</span></div></div></section><!-- Footer CTA --><section class=\"footer-cta\"><div class=\"footer-cta-title\">Ready to take control?</div><p class=\"footer-cta-text\">Self-host NanoTools and enjoy privacy-first utilities on your own server.<br>No data ever leaves your infrastructure.</p><a href=\"https://github.com/tmunongo/nanotools\" class=\"cta-button\"><span>⭐</span> View on GitHub</a></section><!-- Footer --><footer class=\"site-footer\" style=\"margin-top: 4rem;\"><p>Built with ❤️ for privacy-conscious users</p><p>All processing happens on your server • No tracking • Open source</p></footer></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func ColorPalettePage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"colorPalette()\"><div class=\"tool-header\"><div class=\"tool-icon\">🎨</div><h2>Color Palette</h2><p class=\"tool-description\">Pull the dominant colours out of an image, with its average colour, channel histograms and ready-to-use CSS and Tailwind snippets.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"analyze\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload image</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/*,.ico\" required class=\"file-input\"><p class=\"help-text\">Supported: JPEG, PNG, WebP, GIF, BMP, TIFF, ICO, AVIF (max 10MB)</p></div><div class=\"form-section\" x-show=\"preview\" style=\"display: none;\"><div class=\"image-preview\"><img :src=\"preview\" alt=\"Selected image\"></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Colours: <span x-text=\"colors\"></span></label> <input type=\"range\" x-model.number=\"colors\" min=\"1\" max=\"16\" class=\"quality-slider\"></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Method</label> <select x-model=\"method\" class=\"form-input\"><option value=\"kmeans\">k-means (most accurate)</option> <option value=\"median-cut\">Median cut (fastest)</option></select></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"analyzing\"><span x-show=\"!analyzing\">Extract Palette</span> <span x-show=\"analyzing\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Analyzing...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M7 21a4 4 0 01-4-4V5a2 2 0 012-2h4a2 2 0 012 2v12a4 4 0 01-4 4zm0 0h12a2 2 0 002-2v-4a2 2 0 00-2-2h-2.343M11 7.343l1.657-1.657a2 2 0 012.828 0l2.829 2.829a2 2 0 010 2.828l-8.486 8.485M7 17h.01\"></path></svg><p>Your palette will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ <span x-text=\"result?.palette.length\"></span> colours found</span></div><div style=\"display: flex; height: 4rem; border-radius: 0.5rem; overflow: hidden; margin-bottom: 1rem;\"><template x-for=\"c in result?.palette || []\" :key=\"c.hex\"><div :style=\"`background: ${c.hex}; flex: ${c.share} 1 0;`\" :title=\"c.hex + ' · ' + c.share + '%'\"></div></template></div><div style=\"display: grid; gap: 0.5rem;\"><template x-for=\"c in result?.palette || []\" :key=\"c.hex\"><button type=\"button\" @click=\"copy(c.hex)\" class=\"meta-item\" style=\"display: flex; gap: 0.75rem; align-items: center; text-align: left; cursor: pointer; background: none; border: none; padding: 0;\"><span :style=\"`background: ${c.hex}; width: 2.5rem; height: 2.5rem; border-radius: 0.375rem; border: 1px solid #e5e7eb; flex-shrink: 0;`\"></span> <span style=\"flex: 1;\"><strong x-text=\"c.hex\"></strong> <span class=\"help-text\" style=\"display: block;\" x-text=\"`rgb(${c.rgb.join(', ')}) · hsl(${c.hsl[0]}, ${c.hsl[1]}%, ${c.hsl[2]}%)`\"></span></span> <span class=\"meta-value\" x-text=\"c.share + '%'\"></span></button></template></div><p class=\"help-text\">Click a colour to copy its hex code</p><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Average colour:</span> <span class=\"meta-value\" style=\"display: flex; gap: 0.5rem; align-items: center;\"><span :style=\"`background: ${result?.average.hex}; width: 1rem; height: 1rem; border-radius: 0.25rem; display: inline-block;`\"></span> <span x-text=\"result?.average.hex\"></span></span></div><div class=\"meta-item\"><span class=\"meta-label\">Dimensions:</span> <span class=\"meta-value\" x-text=\"result ? result.width + ' × ' + result.height + ' ' + result.format.toUpperCase() : ''\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Transparency:</span> <span class=\"meta-value\" x-text=\"result ? (result.has_alpha ? 'Yes, ' + result.transparent_percent + '% fully transparent' : 'None') : ''\"></span></div></div><div class=\"form-section\"><label class=\"sub-label\">Histogram</label> <svg viewBox=\"0 0 256 100\" preserveAspectRatio=\"none\" style=\"width: 100%; height: 8rem; background: #f9fafb; border-radius: 0.375rem;\"><path :d=\"histogramPath('red')\" fill=\"rgba(239, 68, 68, 0.35)\" stroke=\"#ef4444\" stroke-width=\"0.5\"></path> <path :d=\"histogramPath('green')\" fill=\"rgba(34, 197, 94, 0.35)\" stroke=\"#22c55e\" stroke-width=\"0.5\"></path> <path :d=\"histogramPath('blue')\" fill=\"rgba(59, 130, 246, 0.35)\" stroke=\"#3b82f6\" stroke-width=\"0.5\"></path></svg></div><div class=\"form-section\"><label class=\"sub-label\">CSS custom properties</label> <textarea class=\"json-textarea\" rows=\"6\" readonly x-text=\"result?.css\"></textarea> <button @click=\"copy(result.css)\" class=\"btn btn-secondary\">Copy CSS</button></div><div class=\"form-section\"><label class=\"sub-label\">Tailwind config</label> <textarea class=\"json-textarea\" rows=\"8\" readonly x-text=\"result?.tailwind\"></textarea> <button @click=\"copy(result.tailwind)\" class=\"btn btn-secondary\">Copy Tailwind config</button></div><button @click=\"downloadJSON\" class=\"btn btn-primary btn-full\">Download JSON</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">💡</div><h4 class=\"info-box-title\">How colours are picked</h4></div><p>Colours are grouped by median cut and, by default, refined with k-means. Each share is the percentage of visible pixels closest to that colour. Fully transparent pixels are ignored.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Color Palette").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func FaviconGeneratorPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"faviconGenerator()\"><div class=\"tool-header\"><div class=\"tool-icon\">⭐</div><h2>Favicon Generator</h2><p class=\"tool-description\">Turn one image or SVG into favicon.ico, touch and Android icons, a maskable icon and a web manifest.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"generate\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload source image</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/*,.svg,.ico\" required class=\"file-input\"><p class=\"help-text\">A square image of at least 512×512 or an SVG works best (max 10MB)</p></div><div class=\"form-section\" x-show=\"fileName\" style=\"display: none;\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"fileName\"></p><p class=\"file-size\" x-text=\"fileSize\"></p></div></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Web manifest</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Site name</label> <input type=\"text\" x-model=\"name\" placeholder=\"My Site\" class=\"form-input\"></div><div><label class=\"sub-label\">Short name</label> <input type=\"text\" x-model=\"shortName\" placeholder=\"Same as name\" class=\"form-input\"></div></div><div class=\"color-picker-group\" style=\"margin-top: 1rem;\"><div class=\"color-picker\"><label>Theme:</label> <input type=\"color\" x-model=\"themeColor\" class=\"color-input\"> <span class=\"color-value\" x-text=\"themeColor\"></span></div><div class=\"color-picker\"><label>Background:</label> <input type=\"color\" x-model=\"backgroundColor\" class=\"color-input\"> <span class=\"color-value\" x-text=\"backgroundColor\"></span></div></div><p class=\"help-text\">The background fills the Apple touch icon and the maskable icon</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Maskable padding: <span x-text=\"maskablePadding\"></span>%</label> <input type=\"range\" x-model.number=\"maskablePadding\" min=\"0\" max=\"40\" class=\"quality-slider\"><p class=\"help-text\">Android may crop maskable icons to a circle; 10% keeps the artwork inside the safe zone</p></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"generating\"><span x-show=\"!generating\">Generate Icons</span> <span x-show=\"generating\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Generating...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M11.049 2.927c.3-.921 1.603-.921 1.902 0l1.519 4.674a1 1 0 00.95.69h4.915c.969 0 1.371 1.24.588 1.81l-3.976 2.888a1 1 0 00-.363 1.118l1.518 4.674c.3.922-.755 1.688-1.538 1.118l-3.976-2.888a1 1 0 00-1.176 0l-3.976 2.888c-.783.57-1.838-.197-1.538-1.118l1.518-4.674a1 1 0 00-.362-1.118l-3.976-2.888c-.784-.57-.38-1.81.588-1.81h4.914a1 1 0 00.951-.69l1.519-4.674z\"></path></svg><p>Your icon set will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ Icons ready</span></div><div style=\"display: grid; gap: 0.5rem; grid-template-columns: 1fr 1fr;\"><div><p class=\"sub-label\">Icon</p><div class=\"image-preview\"><img :src=\"pngUrl(result?.preview)\" alt=\"Icon preview\"></div></div><div><p class=\"sub-label\">Maskable (circle crop)</p><div class=\"image-preview\"><img :src=\"pngUrl(result?.maskable)\" alt=\"Maskable icon preview\" style=\"border-radius: 50%; max-width: 192px;\"></div></div></div><div class=\"form-section\"><label class=\"sub-label\">Add to your &lt;head&gt;</label> <textarea class=\"json-textarea\" rows=\"7\" readonly x-text=\"result?.snippet\"></textarea> <button @click=\"copySnippet\" class=\"btn btn-secondary\">Copy snippet</button></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download Icon Set (ZIP)</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">💡</div><h4 class=\"info-box-title\">What's included</h4></div><p>favicon.ico (16, 32 and 48px), favicon-16x16.png, favicon-32x32.png, apple-touch-icon.png (180px), Android icons at 192 and 512px, a 512px maskable icon, site.webmanifest and favicon.html with the tags above. Upload the files to the root of your site.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Favicon Generator").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func ImageCompressorPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"imageCompressor()\"><div class=\"tool-header\"><div class=\"tool-icon\">📦</div><h2>Image Compressor</h2><p class=\"tool-description\">Shrink images to a target file size or dimension while keeping them looking sharp.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"compress\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload image</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/jpeg,image/png,image/webp\" required class=\"file-input\"><p class=\"help-text\">Supported: JPEG, PNG, WebP (max 10MB)</p></div><div class=\"form-section\" x-show=\"fileName\" style=\"display: none;\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"fileName\"></p><p class=\"file-size\" x-text=\"fileSize\"></p></div></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Output Format</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"\" checked x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">Keep</span> <span class=\"format-desc\">Same as input</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"jpeg\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">JPEG</span> <span class=\"format-desc\">Best for photos</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"webp\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">WebP</span> <span class=\"format-desc\">Smallest files</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"png\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">PNG</span> <span class=\"format-desc\">Graphics and transparency</span></span></label></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Settings</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Target size (KB)</label> <input type=\"number\" x-model.number=\"targetKB\" min=\"1\" placeholder=\"No target\" class=\"form-input\"></div><div><label class=\"sub-label\">Max width/height (px)</label> <input type=\"number\" x-model.number=\"maxDimension\" min=\"16\" max=\"10000\" placeholder=\"Keep size\" class=\"form-input\"></div><div x-show=\"!targetKB && outputFormat !== 'png'\"><label class=\"sub-label\">Quality (%)</label> <input type=\"number\" x-model.number=\"quality\" min=\"1\" max=\"100\" class=\"form-input\"></div><div x-show=\"isPNG()\"><label class=\"sub-label\">PNG palette</label> <select x-model.number=\"colors\" class=\"form-input\"><option value=\"0\">Full colour (lossless)</option> <option value=\"256\">256 colours</option> <option value=\"128\">128 colours</option> <option value=\"64\">64 colours</option> <option value=\"32\">32 colours</option> <option value=\"16\">16 colours</option></select></div></div><label class=\"checkbox-label\" x-show=\"targetKB\" style=\"margin-top: 1rem;\"><input type=\"checkbox\" x-model=\"allowDownscale\"> Shrink dimensions if quality alone can't reach the target</label></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"compressing\"><span x-show=\"!compressing\">Compress Image</span> <span x-show=\"compressing\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Compressing...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16l4.586-4.586a2 2 0 012.828 0L16 16m-2-2l1.586-1.586a2 2 0 012.828 0L20 14m-6-6h.01M6 20h12a2 2 0 002-2V6a2 2 0 00-2-2H6a2 2 0 00-2 2v12a2 2 0 002 2z\"></path></svg><p>Your compressed image will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\" x-show=\"result?.target_met\">✓ Saved <span x-text=\"savings()\"></span>%</span> <span class=\"success-badge\" x-show=\"result && !result.target_met\">Target not reached, smallest result shown</span></div><div style=\"display: grid; gap: 0.5rem; grid-template-columns: 1fr 1fr;\"><div><p class=\"sub-label\">Before</p><div class=\"image-preview\"><img :src=\"originalUrl\" alt=\"Original image\"></div></div><div><p class=\"sub-label\">After</p><div class=\"image-preview\"><img :src=\"resultUrl()\" alt=\"Compressed image\"></div></div></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Original:</span> <span class=\"meta-value\" x-text=\"formatBytes(result?.original_size || 0)\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Compressed:</span> <span class=\"meta-value\" x-text=\"formatBytes(result?.compressed_size || 0)\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Dimensions:</span> <span class=\"meta-value\" x-text=\"result ? result.width + ' × ' + result.height : ''\"></span></div><div class=\"meta-item\" x-show=\"result?.quality\"><span class=\"meta-label\">Quality:</span> <span class=\"meta-value\" x-text=\"result?.quality\"></span></div><div class=\"meta-item\" x-show=\"result?.colors\"><span class=\"meta-label\">Colours:</span> <span class=\"meta-value\" x-text=\"result?.colors\"></span></div></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download Compressed Image</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🔒</div><h4 class=\"info-box-title\">Privacy First</h4></div><p>Your images are processed entirely on your server. Nothing is sent to third parties, and nothing is kept after the result is returned.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Image Compressor").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
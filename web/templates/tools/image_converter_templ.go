@@ -43,7 +43,7 @@ func ImageConverterPage() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"imageConverter()\"><div class=\"tool-header\"><div class=\"tool-icon\">🖼️</div><h2>Image Converter</h2><p class=\"tool-description\">Convert images between JPEG, PNG, and WebP formats. Modern, efficient, privacy-respecting.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"convert\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload image</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/jpeg,image/png,image/webp\" required class=\"file-input\"><p class=\"help-text\">Supported: JPEG, PNG, WebP (max 10MB)</p></div><div class=\"form-section\" x-show=\"fileName\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"fileName\"></p><p class=\"file-size\" x-text=\"fileSize\"></p></div></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Convert to</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"jpeg\" checked x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">JPEG</span> <span class=\"format-desc\">Best for photos</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"png\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">PNG</span> <span class=\"format-desc\">Lossless, supports transparency</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"webp\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">WebP</span> <span class=\"format-desc\">Modern, smaller files</span></span></label></div></div><div class=\"form-section\" x-show=\"outputFormat === 'jpeg' || outputFormat === 'webp'\"><label class=\"form-label\"><span class=\"label-dot\"></span> Quality: <span x-text=\"quality\"></span>%</label> <input type=\"range\" x-model.number=\"quality\" min=\"1\" max=\"100\" class=\"quality-slider\"><p class=\"help-text\">Higher quality = larger file size</p></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"converting\"><span x-show=\"!converting\">Convert Image</span> <span x-show=\"converting\" class=\"loading\"><span class=\"spinner\"></span> Converting...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16l4.586-4.586a2 2 0 012.828 0L16 16m-2-2l1.586-1.586a2 2 0 012.828 0L20 14m-6-6h.01M6 20h12a2 2 0 002-2V6a2 2 0 00-2-2H6a2 2 0 00-2 2v12a2 2 0 002 2z\"></path></svg><p>Your converted image will appear here</p></div><div x-show=\"result\" class=\"result-container\"><div class=\"output-header\"><span class=\"success-badge\">✓ Conversion complete</span></div><div class=\"image-preview\"><img :src=\"result\" alt=\"Converted image\"></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Format:</span> <span class=\"meta-value\" x-text=\"resultFormat\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Size:</span> <span class=\"meta-value\" x-text=\"resultSize\"></span></div></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download Converted Image</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🔒</div><h4 class=\"info-box-title\">Privacy First</h4></div><p>Your images are processed entirely on your server. Nothing is sent to third parties, and temporary files are deleted immediately after conversion.</p></div></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"imageConverter()\"><div class=\"tool-header\"><div class=\"tool-icon\">🖼️</div><h2>Image Converter</h2><p class=\"tool-description\">Convert images between JPEG, PNG, WebP, GIF, BMP, TIFF, ICO and AVIF, with optional resize, crop, rotate, flip and watermark.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"convert\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload image</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/jpeg,image/png,image/webp,image/gif,image/bmp,image/tiff,image/x-icon,image/vnd.microsoft.icon,image/avif,image/svg+xml,.ico,.svg,.zip,application/zip\" multiple required class=\"file-input\"><p class=\"help-text\">Supported: JPEG, PNG, WebP, GIF, BMP, TIFF, ICO, AVIF and SVG (max 10MB)</p><p class=\"help-text\">Select several images or a ZIP to convert them all at once (up to 100 images, 50MB total)</p></div><div class=\"form-section\" x-show=\"fileName\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"fileName\"></p><p class=\"file-size\" x-text=\"fileSize\"></p></div></div></div><div class=\"form-section\" x-show=\"metadata\" style=\"display: none;\"><label class=\"form-label\"><span class=\"label-dot\"></span> Metadata</label><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Dimensions:</span> <span class=\"meta-value\" x-text=\"metadata ? metadata.width + ' × ' + metadata.height : ''\"></span></div><div class=\"meta-item\" x-show=\"metadata?.camera\"><span class=\"meta-label\">Camera:</span> <span class=\"meta-value\" x-text=\"metadata?.camera\"></span></div><div class=\"meta-item\" x-show=\"metadata?.lens\"><span class=\"meta-label\">Lens:</span> <span class=\"meta-value\" x-text=\"metadata?.lens\"></span></div><div class=\"meta-item\" x-show=\"metadata?.taken_at\"><span class=\"meta-label\">Taken:</span> <span class=\"meta-value\" x-text=\"metadata?.taken_at\"></span></div><div class=\"meta-item\" x-show=\"metadata?.gps\"><span class=\"meta-label\">Location:</span> <span class=\"meta-value\" x-text=\"metadata?.gps ? metadata.gps.latitude + ', ' + metadata.gps.longitude : ''\"></span></div><div class=\"meta-item\" x-show=\"metadata?.icc_profile\"><span class=\"meta-label\">Colour profile:</span> <span class=\"meta-value\" x-text=\"metadata?.icc_profile\"></span></div><div class=\"meta-item\" x-show=\"metadata?.xmp\"><span class=\"meta-label\">XMP:</span> <span class=\"meta-value\">Present</span></div></div><p class=\"help-text\" x-show=\"metadata?.gps\" style=\"color: #b45309;\">⚠️ This photo contains its GPS location. Remove metadata before sharing it.</p><p class=\"help-text\" x-show=\"metadata && !hasMetadata()\">No EXIF, XMP or colour profile found</p><button type=\"button\" @click=\"stripMetadata\" class=\"btn btn-secondary btn-full\" x-show=\"hasMetadata()\" :disabled=\"stripping\" style=\"margin-top: 0.5rem;\"><span x-show=\"!stripping\">Download without metadata</span> <span x-show=\"stripping\" style=\"display: none;\">Removing metadata...</span></button><p class=\"help-text\">Converted images never include metadata, and photos are rotated upright automatically</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Convert to</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"jpeg\" checked x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">JPEG</span> <span class=\"format-desc\">Best for photos</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"png\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">PNG</span> <span class=\"format-desc\">Lossless, supports transparency</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"webp\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">WebP</span> <span class=\"format-desc\">Modern, smaller files</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"avif\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">AVIF</span> <span class=\"format-desc\">Smallest, newer browsers</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"gif\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">GIF</span> <span class=\"format-desc\">Keeps animation</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"ico\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">ICO</span> <span class=\"format-desc\">Icons, up to 256px</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"bmp\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">BMP</span> <span class=\"format-desc\">Uncompressed</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"format\" value=\"tiff\" x-model=\"outputFormat\"> <span class=\"format-card\"><span class=\"format-name\">TIFF</span> <span class=\"format-desc\">Print and archiving</span></span></label></div></div><div class=\"form-section\" x-show=\"isGIF && outputFormat !== 'gif'\" style=\"display: none;\"><label class=\"form-label\"><span class=\"label-dot\"></span> Frame</label> <input type=\"number\" x-model.number=\"frame\" min=\"1\" class=\"form-input\"><p class=\"help-text\">Which frame of the animation to export; converting to GIF keeps them all</p></div><div class=\"form-section\" x-show=\"outputFormat === 'jpeg' || outputFormat === 'webp' || outputFormat === 'avif'\"><label class=\"form-label\"><span class=\"label-dot\"></span> Quality: <span x-text=\"quality\"></span>%</label> <input type=\"range\" x-model.number=\"quality\" min=\"1\" max=\"100\" class=\"quality-slider\"><p class=\"help-text\">Higher quality = larger file size</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Edit</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Resize</label> <select x-model=\"resizeMode\" class=\"form-input\"><option value=\"\">Keep size</option> <option value=\"exact\">Exact size</option> <option value=\"fit\">Fit inside</option> <option value=\"fill\">Fill and crop</option> <option value=\"percent\">Percentage</option></select></div><div x-show=\"resizeMode === 'percent'\" style=\"display: none;\"><label class=\"sub-label\">Scale (%)</label> <input type=\"number\" x-model.number=\"resizePercent\" min=\"1\" max=\"1000\" class=\"form-input\"></div><div x-show=\"resizeMode && resizeMode !== 'percent'\" style=\"display: none;\"><label class=\"sub-label\">Width (px)</label> <input type=\"number\" x-model.number=\"resizeWidth\" min=\"1\" max=\"10000\" class=\"form-input\"></div><div x-show=\"resizeMode && resizeMode !== 'percent'\" style=\"display: none;\"><label class=\"sub-label\">Height (px)</label> <input type=\"number\" x-model.number=\"resizeHeight\" min=\"1\" max=\"10000\" class=\"form-input\" :placeholder=\"resizeMode === 'exact' ? 'Keep aspect ratio' : ''\"></div><div><label class=\"sub-label\">Rotate</label> <select x-model.number=\"rotate\" class=\"form-input\"><option value=\"0\">None</option> <option value=\"90\">90° clockwise</option> <option value=\"180\">180°</option> <option value=\"270\">90° counter-clockwise</option></select></div><div><label class=\"sub-label\">Flip</label> <label class=\"checkbox-label\"><input type=\"checkbox\" x-model=\"flipHorizontal\"> Horizontal</label> <label class=\"checkbox-label\"><input type=\"checkbox\" x-model=\"flipVertical\"> Vertical</label></div></div><label class=\"checkbox-label\" style=\"margin-top: 1rem;\"><input type=\"checkbox\" x-model=\"cropEnabled\"> Crop before resizing</label><div x-show=\"cropEnabled\" class=\"settings-grid\" style=\"display: none; gap: 1rem; grid-template-columns: 1fr 1fr; margin-top: 0.5rem;\"><div><label class=\"sub-label\">Left (px)</label> <input type=\"number\" x-model.number=\"cropX\" min=\"0\" class=\"form-input\"></div><div><label class=\"sub-label\">Top (px)</label> <input type=\"number\" x-model.number=\"cropY\" min=\"0\" class=\"form-input\"></div><div><label class=\"sub-label\">Width (px)</label> <input type=\"number\" x-model.number=\"cropWidth\" min=\"1\" class=\"form-input\"></div><div><label class=\"sub-label\">Height (px)</label> <input type=\"number\" x-model.number=\"cropHeight\" min=\"1\" class=\"form-input\"></div></div><p class=\"help-text\">Applied in order: crop, resize, rotate, flip</p></div><div class=\"form-section\"><label class=\"checkbox-label\"><input type=\"checkbox\" x-model=\"watermarkEnabled\"> Add a watermark</label><div x-show=\"watermarkEnabled\" style=\"display: none; margin-top: 0.5rem;\"><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Type</label> <select x-model=\"watermarkType\" class=\"form-input\"><option value=\"text\">Text</option> <option value=\"logo\">Logo</option></select></div><div x-show=\"watermarkType === 'text'\"><label class=\"sub-label\">Text</label> <input type=\"text\" x-model=\"watermarkText\" maxlength=\"200\" placeholder=\"© Your Name\" class=\"form-input\"></div><div x-show=\"watermarkType === 'logo'\" style=\"display: none;\"><label class=\"sub-label\">Logo (PNG or SVG)</label> <input type=\"file\" @change=\"watermarkLogo = $event.target.files[0] || null\" accept=\"image/png,image/webp,image/svg+xml,.svg\" class=\"file-input\"></div><div x-show=\"watermarkType === 'text'\"><label class=\"sub-label\">Font size (px)</label> <input type=\"number\" x-model.number=\"watermarkFontSize\" min=\"1\" max=\"1000\" placeholder=\"Auto\" class=\"form-input\"></div><div x-show=\"watermarkType === 'text'\"><label class=\"sub-label\">Colour</label> <input type=\"color\" x-model=\"watermarkColor\" class=\"form-input\"></div><div x-show=\"watermarkType === 'logo'\" style=\"display: none;\"><label class=\"sub-label\">Logo width: <span x-text=\"watermarkScale\"></span>%</label> <input type=\"range\" x-model.number=\"watermarkScale\" min=\"5\" max=\"100\" class=\"quality-slider\"></div><div><label class=\"sub-label\">Opacity: <span x-text=\"watermarkOpacity\"></span>%</label> <input type=\"range\" x-model.number=\"watermarkOpacity\" min=\"5\" max=\"100\" class=\"quality-slider\"></div><div x-show=\"!watermarkTile\"><label class=\"sub-label\">Position</label> <select x-model=\"watermarkPosition\" class=\"form-input\"><option value=\"top-left\">Top left</option> <option value=\"top\">Top</option> <option value=\"top-right\">Top right</option> <option value=\"left\">Left</option> <option value=\"center\">Centre</option> <option value=\"right\">Right</option> <option value=\"bottom-left\">Bottom left</option> <option value=\"bottom\">Bottom</option> <option value=\"bottom-right\">Bottom right</option></select></div><div><label class=\"sub-label\">Angle (°)</label> <input type=\"number\" x-model.number=\"watermarkAngle\" min=\"-180\" max=\"180\" class=\"form-input\"></div></div><label class=\"checkbox-label\" style=\"margin-top: 1rem;\"><input type=\"checkbox\" x-model=\"watermarkTile\"> Repeat across the whole image</label></div><p class=\"help-text\">Applied after the edits above, to every frame of animations</p></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"converting\"><span x-show=\"!converting\" x-text=\"batch ? 'Convert All Images' : 'Convert Image'\">Convert Image</span> <span x-show=\"converting\" class=\"loading\"><span class=\"spinner\"></span> Converting...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\"></div></div><div class=\"output-section\"><div x-show=\"!result && !batchResult\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16l4.586-4.586a2 2 0 012.828 0L16 16m-2-2l1.586-1.586a2 2 0 012.828 0L20 14m-6-6h.01M6 20h12a2 2 0 002-2V6a2 2 0 00-2-2H6a2 2 0 00-2 2v12a2 2 0 002 2z\"></path></svg><p>Your converted image will appear here</p></div><div x-show=\"batchResult\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ Batch complete</span></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Format:</span> <span class=\"meta-value\" x-text=\"batchResult?.format\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">ZIP size:</span> <span class=\"meta-value\" x-text=\"batchResult?.size\"></span></div></div><p class=\"help-text\">The archive includes report.json listing every file and any that could not be converted.</p><button @click=\"download\" class=\"btn btn-primary btn-full\">Download ZIP</button></div><div x-show=\"result\" class=\"result-container\"><div class=\"output-header\"><span class=\"success-badge\">✓ Conversion complete</span></div><div class=\"image-preview\"><img :src=\"result\" alt=\"Converted image\" @load=\"resultDimensions = $event.target.naturalWidth + ' × ' + $event.target.naturalHeight\"></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Format:</span> <span class=\"meta-value\" x-text=\"resultFormat\"></span></div><div class=\"meta-item\" x-show=\"resultDimensions\"><span class=\"meta-label\">Dimensions:</span> <span class=\"meta-value\" x-text=\"resultDimensions\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Size:</span> <span class=\"meta-value\" x-text=\"resultSize\"></span></div></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download Converted Image</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🔒</div><h4 class=\"info-box-title\">Privacy First</h4></div><p>Your images are processed entirely on your server. Nothing is sent to third parties, and temporary files are deleted immediately after conversion.</p></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
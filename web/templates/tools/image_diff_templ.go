@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func ImageDiffPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"imageDiff()\"><div class=\"tool-header\"><div class=\"tool-icon\">🔍</div><h2>Image Diff</h2><p class=\"tool-description\">Compare two screenshots or renders, highlight what changed and measure how similar they are.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"compare\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Before</label> <input type=\"file\" @change=\"handleFileSelect($event, 'before')\" accept=\"image/*,.ico\" required class=\"file-input\"><p class=\"help-text\" x-show=\"before\" x-text=\"before ? before.name + ' · ' + formatBytes(before.size) : ''\"></p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> After</label> <input type=\"file\" @change=\"handleFileSelect($event, 'after')\" accept=\"image/*,.ico\" required class=\"file-input\"><p class=\"help-text\" x-show=\"after\" x-text=\"after ? after.name + ' · ' + formatBytes(after.size) : ''\"></p><p class=\"help-text\">Supported: JPEG, PNG, WebP, GIF, BMP, TIFF, ICO, AVIF (max 10MB each)</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Tolerance: <span x-text=\"threshold\"></span></label> <input type=\"range\" x-model.number=\"threshold\" min=\"0\" max=\"128\" class=\"quality-slider\"><p class=\"help-text\">Colour differences up to this much (out of 255) are ignored; raise it for JPEGs</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Different sizes</label> <select x-model=\"align\" class=\"form-input\"><option value=\"resize\">Scale the second image to match the first</option> <option value=\"pad\">Align top-left and mark the extra area as changed</option></select></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"comparing\"><span x-show=\"!comparing\">Compare Images</span> <span x-show=\"comparing\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Comparing...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z\"></path></svg><p>The differences will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\" x-text=\"result?.metrics.changed_pixels === 0 ? '✓ Images are identical' : '✓ Comparison complete'\"></span></div><div class=\"image-preview\"><img :src=\"result ? 'data:image/png;base64,' + result.diff : ''\" alt=\"Differences\"></div><p class=\"help-text\">Changed pixels are red, with each changed region boxed in magenta</p><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Changed:</span> <span class=\"meta-value\" x-text=\"result ? result.metrics.changed_percent + '% (' + result.metrics.changed_pixels.toLocaleString() + ' pixels)' : ''\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Regions:</span> <span class=\"meta-value\" x-text=\"result?.metrics.total_regions\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">PSNR:</span> <span class=\"meta-value\" x-text=\"result ? (result.metrics.psnr >= 100 ? '∞' : result.metrics.psnr + ' dB') : ''\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">SSIM:</span> <span class=\"meta-value\" x-text=\"result?.metrics.ssim\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Compared at:</span> <span class=\"meta-value\" x-text=\"result ? result.metrics.width + ' × ' + result.metrics.height + (result.metrics.resized ? ' (second image scaled)' : '') : ''\"></span></div></div><div style=\"display: flex; gap: 0.5rem;\"><button @click=\"downloadDiff\" class=\"btn btn-primary\" style=\"flex: 1;\">Download Diff Image</button> <button @click=\"downloadReport\" class=\"btn btn-secondary\" style=\"flex: 1;\">Download JSON</button></div></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">💡</div><h4 class=\"info-box-title\">Reading the numbers</h4></div><p>PSNR above 40 dB is usually invisible to the eye. SSIM runs from 0 to 1, where 1 means structurally identical. The JSON report lists every changed region's position and size.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Image Diff").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
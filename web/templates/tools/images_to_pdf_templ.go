@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func ImagesToPDFPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"imagesToPdf()\"><div class=\"tool-header\"><div class=\"tool-icon\">📑</div><h2>Images to PDF</h2><p class=\"tool-description\">Combine JPEG, PNG and WebP images into a single PDF, one image per page.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"build\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload images</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/jpeg,image/png,image/webp\" multiple required class=\"file-input\"><p class=\"help-text\">Supported: JPEG, PNG, WebP (max 50MB in total)</p></div><div class=\"form-section\" x-show=\"files.length > 0\" style=\"display: none;\"><template x-for=\"(file, index) in files\" :key=\"file.name + index\"><div class=\"file-info\" style=\"margin-bottom: 0.5rem;\"><div class=\"file-icon\">🖼️</div><div class=\"file-details\" style=\"flex: 1;\"><p class=\"file-name\" x-text=\"'Page ' + (index + 1) + ': ' + file.name\"></p><p class=\"file-size\" x-text=\"formatBytes(file.size)\"></p></div><div style=\"display: flex; gap: 0.25rem;\"><button type=\"button\" class=\"btn btn-secondary btn-sm\" @click=\"moveFile(index, -1)\" :disabled=\"index === 0\">↑</button> <button type=\"button\" class=\"btn btn-secondary btn-sm\" @click=\"moveFile(index, 1)\" :disabled=\"index === files.length - 1\">↓</button></div></div></template></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Page Size</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"page_size\" value=\"a4\" checked x-model=\"pageSize\"> <span class=\"format-card\"><span class=\"format-name\">A4</span> <span class=\"format-desc\">210 × 297 mm</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"page_size\" value=\"letter\" x-model=\"pageSize\"> <span class=\"format-card\"><span class=\"format-name\">Letter</span> <span class=\"format-desc\">8.5 × 11 in</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"page_size\" value=\"fit\" x-model=\"pageSize\"> <span class=\"format-card\"><span class=\"format-name\">Fit</span> <span class=\"format-desc\">Page matches image</span></span></label></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Settings</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr 1fr;\"><div x-show=\"pageSize !== 'fit'\"><label class=\"sub-label\">Orientation</label> <select x-model=\"orientation\" class=\"form-input\"><option value=\"auto\">Auto</option> <option value=\"portrait\">Portrait</option> <option value=\"landscape\">Landscape</option></select></div><div><label class=\"sub-label\">Margin (pt)</label> <input type=\"number\" x-model.number=\"margin\" min=\"0\" max=\"144\" class=\"input-field\" style=\"width: 100%; padding: 0.5rem; border: 1px solid #e5e7eb; border-radius: 0.375rem;\"></div><div><label class=\"sub-label\">JPEG Quality (%)</label> <input type=\"number\" x-model.number=\"quality\" min=\"1\" max=\"100\" class=\"input-field\" style=\"width: 100%; padding: 0.5rem; border: 1px solid #e5e7eb; border-radius: 0.375rem;\"></div></div></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"building\"><span x-show=\"!building\">Create PDF</span> <span x-show=\"building\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Building PDF...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!resultBlob\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p>Your PDF will appear here</p></div><div x-show=\"resultBlob\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ <span x-text=\"files.length\"></span> pages</span></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Size:</span> <span class=\"meta-value\" x-text=\"formatBytes(resultBlob?.size || 0)\"></span></div></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download PDF</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🔒</div><h4 class=\"info-box-title\">Privacy First</h4></div><p>Your images are processed entirely on your server. Nothing is sent to third parties.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Images to PDF").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
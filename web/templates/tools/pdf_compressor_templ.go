@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func PDFCompressorPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"pdfCompressor()\"><div class=\"tool-header\"><div class=\"tool-icon\">🗜️</div><h2>PDF Compressor</h2><p class=\"tool-description\">Shrink oversized PDFs by downsampling images and optimizing their structure.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"compress\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload PDF</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\".pdf\" required class=\"file-input\"><p class=\"help-text\">Max size: 50MB</p></div><div class=\"form-section\" x-show=\"fileName\" style=\"display: none;\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"fileName\"></p><p class=\"file-size\" x-text=\"fileSize\"></p></div></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Quality Preset</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"preset\" value=\"screen\" x-model=\"preset\"> <span class=\"format-card\"><span class=\"format-name\">Screen</span> <span class=\"format-desc\">Smallest, 72 DPI</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"preset\" value=\"ebook\" checked x-model=\"preset\"> <span class=\"format-card\"><span class=\"format-name\">eBook</span> <span class=\"format-desc\">Balanced, 150 DPI</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"preset\" value=\"printer\" x-model=\"preset\"> <span class=\"format-card\"><span class=\"format-name\">Printer</span> <span class=\"format-desc\">High quality, 300 DPI</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"preset\" value=\"prepress\" x-model=\"preset\"> <span class=\"format-card\"><span class=\"format-name\">Prepress</span> <span class=\"format-desc\">Color preserving</span></span></label></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Settings</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Image DPI (optional)</label> <input type=\"number\" x-model.number=\"dpi\" min=\"36\" max=\"600\" placeholder=\"Preset default\" class=\"input-field\" style=\"width: 100%; padding: 0.5rem; border: 1px solid #e5e7eb; border-radius: 0.375rem;\"></div><div><label class=\"checkbox-label\"><input type=\"checkbox\" x-model=\"grayscale\"> Convert to grayscale</label></div></div></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"compressing\"><span x-show=\"!compressing\">Compress PDF</span> <span x-show=\"compressing\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Compressing...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p>Your compressed PDF will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ Saved <span x-text=\"savings()\"></span>%</span></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Original:</span> <span class=\"meta-value\" x-text=\"formatBytes(result?.original_size || 0)\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Compressed:</span> <span class=\"meta-value\" x-text=\"formatBytes(result?.compressed_size || 0)\"></span></div></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download Compressed PDF</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🔒</div><h4 class=\"info-box-title\">Privacy First</h4></div><p>Your PDFs are processed entirely on your server. If compression would make the file larger, you keep the original and nothing is returned.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("PDF Compressor").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func PDFOrganizerPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"pdfOrganizer()\"><div class=\"tool-header\"><div class=\"tool-icon\">🗂️</div><h2>PDF Organizer</h2><p class=\"tool-description\">Merge, split, rotate, reorder and delete PDF pages. Private and secure processing.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"process\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Operation</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"mode\" value=\"merge\" x-model=\"mode\" @change=\"reset\"> <span class=\"format-card\"><span class=\"format-name\">Merge</span> <span class=\"format-desc\">Combine several PDFs</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"mode\" value=\"split\" x-model=\"mode\" @change=\"reset\"> <span class=\"format-card\"><span class=\"format-name\">Split</span> <span class=\"format-desc\">Cut into page ranges</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"mode\" value=\"rotate\" x-model=\"mode\" @change=\"reset\"> <span class=\"format-card\"><span class=\"format-name\">Rotate</span> <span class=\"format-desc\">Turn selected pages</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"mode\" value=\"reorder\" x-model=\"mode\" @change=\"reset\"> <span class=\"format-card\"><span class=\"format-name\">Reorder</span> <span class=\"format-desc\">Reorder or delete pages</span></span></label></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> <span x-text=\"mode === 'merge' ? 'Upload PDFs' : 'Upload PDF'\"></span></label> <input type=\"file\" @change=\"handleFileSelect\" accept=\".pdf\" :multiple=\"mode === 'merge'\" required class=\"file-input\"><p class=\"help-text\">Max size: 50MB in total</p></div><div class=\"form-section\" x-show=\"files.length > 0\" style=\"display: none;\"><template x-for=\"(file, index) in files\" :key=\"file.name + index\"><div class=\"file-info\" style=\"margin-bottom: 0.5rem;\"><div class=\"file-icon\">📁</div><div class=\"file-details\" style=\"flex: 1;\"><p class=\"file-name\" x-text=\"(index + 1) + '. ' + file.name\"></p><p class=\"file-size\" x-text=\"formatBytes(file.size)\"></p></div><div x-show=\"mode === 'merge'\" style=\"display: flex; gap: 0.25rem;\"><button type=\"button\" class=\"btn btn-secondary btn-sm\" @click=\"moveFile(index, -1)\" :disabled=\"index === 0\">↑</button> <button type=\"button\" class=\"btn btn-secondary btn-sm\" @click=\"moveFile(index, 1)\" :disabled=\"index === files.length - 1\">↓</button></div></div></template></div><div class=\"form-section\" x-show=\"mode === 'split'\" style=\"display: none;\"><label class=\"form-label\"><span class=\"label-dot\"></span> Page Ranges</label> <input type=\"text\" x-model=\"ranges\" placeholder=\"1-3, 4, 5-\" class=\"form-input\"><p class=\"help-text\">Each range becomes its own file. Leave empty to split every page.</p></div><div class=\"form-section\" x-show=\"mode === 'rotate'\" style=\"display: none;\"><label class=\"form-label\"><span class=\"label-dot\"></span> Pages & Angle</label> <input type=\"text\" x-model=\"pages\" placeholder=\"1, 3-5 (empty for all pages)\" class=\"form-input\"> <select x-model.number=\"angle\" class=\"form-input\" style=\"margin-top: 0.5rem;\"><option value=\"90\">90° clockwise</option> <option value=\"180\">180°</option> <option value=\"270\">90° counter-clockwise</option></select></div><div class=\"form-section\" x-show=\"mode === 'reorder'\" style=\"display: none;\"><label class=\"form-label\"><span class=\"label-dot\"></span> New Page Order</label> <input type=\"text\" x-model=\"order\" placeholder=\"3, 1-2, 5\" class=\"form-input\"><p class=\"help-text\">Pages left out of the list are deleted.</p></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"processing\"><span x-show=\"!processing\">Process PDF</span> <span x-show=\"processing\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Processing PDF...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!resultBlob\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p>Your processed document will appear here</p></div><div x-show=\"resultBlob\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ Done</span></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">File:</span> <span class=\"meta-value\" x-text=\"resultName\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Size:</span> <span class=\"meta-value\" x-text=\"formatBytes(resultBlob?.size || 0)\"></span></div></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🔒</div><h4 class=\"info-box-title\">Privacy First</h4></div><p>Your PDFs are processed entirely on your server. Nothing is sent to third parties, and temporary files are deleted immediately after processing.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("PDF Organizer").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
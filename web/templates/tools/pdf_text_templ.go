@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func PDFTextPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"pdfText()\"><div class=\"tool-header\"><div class=\"tool-icon\">🔤</div><h2>PDF to Text</h2><p class=\"tool-description\">Extract the text from a PDF as plain text, Markdown or per-page JSON.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"extract\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload PDF</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\".pdf\" required class=\"file-input\"><p class=\"help-text\">Max size: 50MB</p></div><div class=\"form-section\" x-show=\"fileName\" style=\"display: none;\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"fileName\"></p><p class=\"file-size\" x-text=\"fileSize\"></p></div></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Output</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"output\" value=\"text\" checked x-model=\"output\"> <span class=\"format-card\"><span class=\"format-name\">Text</span> <span class=\"format-desc\">Plain text</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"output\" value=\"markdown\" x-model=\"output\"> <span class=\"format-card\"><span class=\"format-name\">Markdown</span> <span class=\"format-desc\">Page headings</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"output\" value=\"json\" x-model=\"output\"> <span class=\"format-card\"><span class=\"format-name\">JSON</span> <span class=\"format-desc\">One entry per page</span></span></label></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Pages (optional)</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">First page</label> <input type=\"number\" x-model.number=\"firstPage\" min=\"1\" class=\"input-field\" style=\"width: 100%; padding: 0.5rem; border: 1px solid #e5e7eb; border-radius: 0.375rem;\"></div><div><label class=\"sub-label\">Last page</label> <input type=\"number\" x-model.number=\"lastPage\" min=\"1\" class=\"input-field\" style=\"width: 100%; padding: 0.5rem; border: 1px solid #e5e7eb; border-radius: 0.375rem;\"></div></div></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"extracting\"><span x-show=\"!extracting\">Extract Text</span> <span x-show=\"extracting\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Extracting...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p>Extracted text will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ <span x-text=\"result?.count\"></span> pages extracted</span></div><div x-show=\"result?.empty_pages?.length > 0\" class=\"info-box info-box-warning\"><p>No text found on page(s) <strong x-text=\"result?.empty_pages?.join(', ')\"></strong>. These are probably scanned images and would need OCR.</p></div><textarea class=\"json-textarea\" rows=\"20\" readonly x-text=\"display()\"></textarea><div style=\"display: flex; gap: 0.5rem; margin-top: 1rem;\"><button @click=\"copy\" class=\"btn btn-secondary\" style=\"flex: 1;\">Copy</button> <button @click=\"download\" class=\"btn btn-primary\" style=\"flex: 1;\">Download .txt</button></div></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🔒</div><h4 class=\"info-box-title\">Privacy First</h4></div><p>Your PDFs are processed entirely on your server. Nothing is sent to third parties, and temporary files are deleted immediately after extraction.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("PDF to Text").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func PlaylistDownloaderPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"playlistDownloader()\"><div class=\"tool-header\"><div class=\"tool-icon\">📚</div><h2>Playlist Downloader</h2><p class=\"tool-description\">Archive a playlist or channel: pick the entries you need and download them in the background.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"listEntries\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Playlist or channel URL</label> <input type=\"url\" x-model=\"url\" placeholder=\"https://vimeo.com/showcase/...\" required class=\"form-input\"><p class=\"help-text\">YouTube playlists are currently not supported</p></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-secondary btn-full\" :disabled=\"loading || job?.status === 'running'\"><span x-show=\"!loading\">List Entries</span> <span x-show=\"loading\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Loading...</span></button></div></form><div x-show=\"playlist && !job\" style=\"display: none;\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Entries</label><div style=\"display: flex; gap: 0.5rem;\"><input type=\"text\" x-model=\"range\" placeholder=\"e.g. 1-10, 15\" class=\"form-input\"> <button type=\"button\" @click=\"applyRange\" class=\"btn btn-secondary\">Select</button></div><p class=\"help-text\"><span x-text=\"selected.length\"></span> selected (max <span x-text=\"maxItems\"></span>) · <span x-text=\"formatDuration(selectedDuration())\"></span> <span x-show=\"selectedSize()\">· about <span x-text=\"formatBytes(selectedSize())\"></span></span></p><div style=\"max-height: 320px; overflow-y: auto;\"><template x-for=\"entry in playlist?.entries || []\" :key=\"entry.index\"><label class=\"checkbox-label\"><input type=\"checkbox\" :value=\"entry.index\" x-model.number=\"selected\"> <span x-text=\"entry.index + '. ' + entry.title\"></span> <span class=\"help-text\" x-text=\"' ' + formatDuration(entry.duration) + (entry.filesize ? ' · ' + formatBytes(entry.filesize) : '')\"></span></label></template></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Quality</label> <select x-model=\"quality\" class=\"form-input\"><option value=\"1080p\">1080p</option> <option value=\"720p\">720p</option> <option value=\"480p\">480p</option> <option value=\"360p\">360p</option> <option value=\"audio\">Audio only</option></select> <select x-show=\"quality === 'audio'\" x-model=\"audioExt\" class=\"form-input\" style=\"margin-top: 0.5rem;\"><option value=\"mp3\">MP3</option> <option value=\"m4a\">M4A (AAC)</option> <option value=\"opus\">Opus</option></select></div><div class=\"form-actions\"><button type=\"button\" @click=\"startDownload\" class=\"btn btn-primary btn-full\" :disabled=\"starting || !selected.length\"><span x-show=\"!starting\">Download Selected</span> <span x-show=\"starting\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Starting...</span></button></div></div><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!playlist && !job\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 6h16M4 10h16M4 14h16M4 18h16\"></path></svg><p>Enter a playlist URL to get started</p></div><div x-show=\"playlist && !job\" class=\"result-container\" style=\"display: none;\"><h3 x-text=\"playlist?.title\"></h3><p class=\"help-text\" x-show=\"playlist?.uploader\">by <span x-text=\"playlist?.uploader\"></span></p><p class=\"help-text\"><span x-text=\"playlist?.entries.length\"></span> entries listed</p></div><div x-show=\"job\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\" x-text=\"job?.status === 'running' ? 'Downloading ' + doneCount() + ' of ' + job?.items.length : '✓ Finished'\"></span></div><div class=\"result-meta\"><template x-for=\"item in job?.items || []\" :key=\"item.index\"><div class=\"meta-item\"><span class=\"meta-label\" x-text=\"item.index + '. ' + item.title\"></span> <span class=\"meta-value\"><a x-show=\"item.status === 'done'\" :href=\"fileUrl(item)\" x-text=\"'⬇ ' + formatBytes(item.size)\"></a> <span x-show=\"item.status !== 'done'\" x-text=\"statusLabel(item)\" :title=\"item.error\"></span></span></div></template></div><a x-show=\"doneCount() > 0 && job?.status === 'done'\" :href=\"archiveUrl()\" class=\"btn btn-primary btn-full\">Download All (ZIP, <span x-text=\"formatBytes(job?.total_size || 0)\"></span>)</a> <button @click=\"cancel\" x-show=\"job?.status === 'running'\" class=\"btn btn-secondary btn-full\" :disabled=\"cancelling\">Stop Downloading</button> <button @click=\"reset\" x-show=\"job?.status === 'done'\" class=\"btn btn-secondary btn-full\" style=\"margin-top: 0.5rem;\">Start Over</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">⏳</div><h4 class=\"info-box-title\">Downloads run in the background</h4></div><p>Entries are downloaded one at a time, so you can leave this page open and come back. Jobs are limited to 50 entries, 6 hours of video and 2GB in total, and files are deleted an hour after the job finishes.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Playlist Downloader").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func ResponsiveImagesPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"responsiveImages()\"><div class=\"tool-header\"><div class=\"tool-icon\">📐</div><h2>Responsive Images</h2><p class=\"tool-description\">Generate every width and format a page needs from one image, with ready-to-paste &lt;picture&gt; markup and a blurred placeholder.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"generate\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload image</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/*,.ico\" required class=\"file-input\"><p class=\"help-text\">Use the largest version you have; widths bigger than the source are capped at its width (max 10MB)</p></div><div class=\"form-section\" x-show=\"fileName\" style=\"display: none;\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"fileName\"></p><p class=\"file-size\" x-text=\"fileSize\"></p></div></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Widths (px)</label> <input type=\"text\" x-model=\"widths\" placeholder=\"320, 640, 1024, 1920\" class=\"form-input\"><p class=\"help-text\">Comma separated, up to 12 widths</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Formats</label><div style=\"display: flex; gap: 1rem; flex-wrap: wrap;\"><label class=\"checkbox-label\"><input type=\"checkbox\" value=\"avif\" x-model=\"formats\"> AVIF</label> <label class=\"checkbox-label\"><input type=\"checkbox\" value=\"webp\" x-model=\"formats\"> WebP</label> <label class=\"checkbox-label\"><input type=\"checkbox\" value=\"jpeg\" x-model=\"formats\"> JPEG</label> <label class=\"checkbox-label\"><input type=\"checkbox\" value=\"png\" x-model=\"formats\"> PNG</label></div><p class=\"help-text\">The last format in this order is used for the &lt;img&gt; fallback</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Quality: <span x-text=\"quality\"></span>%</label> <input type=\"range\" x-model.number=\"quality\" min=\"1\" max=\"100\" class=\"quality-slider\"></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Markup</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">sizes</label> <input type=\"text\" x-model=\"sizes\" placeholder=\"100vw\" class=\"form-input\"></div><div><label class=\"sub-label\">alt text</label> <input type=\"text\" x-model=\"alt\" class=\"form-input\"></div></div></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"generating\"><span x-show=\"!generating\">Generate Images</span> <span x-show=\"generating\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Generating...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 8V4m0 0h4M4 4l5 5m11-1V4m0 0h-4m4 0l-5 5M4 16v4m0 0h4m-4 0l5-5m11 5l-5-5m5 5v-4m0 4h-4\"></path></svg><p>Your image set will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ <span x-text=\"result?.manifest.images.length\"></span> images ready</span></div><div class=\"result-meta\"><template x-for=\"img in result?.manifest.images || []\" :key=\"img.file\"><div class=\"meta-item\"><span class=\"meta-label\" x-text=\"img.file\"></span> <span class=\"meta-value\" x-text=\"img.width + ' × ' + img.height + ' · ' + formatBytes(img.size)\"></span></div></template></div><div class=\"form-section\"><label class=\"sub-label\">Placeholder</label><div style=\"display: flex; gap: 1rem; align-items: center;\"><img :src=\"result?.manifest.lqip\" alt=\"Placeholder preview\" style=\"width: 120px; filter: blur(8px); border-radius: 4px;\"><div><p class=\"help-text\">BlurHash</p><code x-text=\"result?.manifest.blurhash\"></code></div></div></div><div class=\"form-section\"><label class=\"sub-label\">&lt;picture&gt; markup</label> <textarea class=\"json-textarea\" rows=\"8\" readonly x-text=\"result?.manifest.html\"></textarea> <button @click=\"copySnippet\" class=\"btn btn-secondary\">Copy markup</button></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download Images (ZIP)</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">💡</div><h4 class=\"info-box-title\">What's included</h4></div><p>The ZIP holds every image, picture.html with the markup above and manifest.json listing each file's size and dimensions along with the BlurHash and a tiny data URI placeholder.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Responsive Images").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
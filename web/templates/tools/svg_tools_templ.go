@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func SVGToolsPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"svgTools()\"><div class=\"tool-header\"><div class=\"tool-icon\">✒️</div><h2>SVG Tools</h2><p class=\"tool-description\">Minify SVG markup, strip scripts and external references, or render it to PNG and WebP at any size.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"run\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Upload SVG</label> <input type=\"file\" @change=\"handleFileSelect\" accept=\"image/svg+xml,.svg\" class=\"file-input\"><p class=\"help-text\">Or paste the markup below (max 10MB)</p><textarea x-model=\"markup\" x-show=\"!file\" rows=\"6\" class=\"json-textarea\" placeholder=\"<svg xmlns=&quot;http://www.w3.org/2000/svg&quot; ...>\"></textarea></div><div class=\"form-section\" x-show=\"file\" style=\"display: none;\"><div class=\"file-info\"><div class=\"file-icon\">📁</div><div class=\"file-details\"><p class=\"file-name\" x-text=\"file?.name\"></p><p class=\"file-size\" x-text=\"file ? formatBytes(file.size) : ''\"></p></div></div></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Action</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"mode\" value=\"optimize\" x-model=\"mode\"> <span class=\"format-card\"><span class=\"format-name\">Optimize</span> <span class=\"format-desc\">Minify and clean up</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"mode\" value=\"sanitize\" x-model=\"mode\"> <span class=\"format-card\"><span class=\"format-name\">Sanitize</span> <span class=\"format-desc\">Only remove unsafe content</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"mode\" value=\"rasterize\" x-model=\"mode\"> <span class=\"format-card\"><span class=\"format-name\">Rasterize</span> <span class=\"format-desc\">PNG or WebP</span></span></label></div></div><div class=\"form-section\" x-show=\"mode === 'optimize'\"><label class=\"form-label\"><span class=\"label-dot\"></span> Decimal places: <span x-text=\"precision\"></span></label> <input type=\"range\" x-model.number=\"precision\" min=\"0\" max=\"8\" class=\"quality-slider\"><p class=\"help-text\">Coordinates are rounded to this many places; 2-3 is usually indistinguishable</p><label class=\"checkbox-label\"><input type=\"checkbox\" x-model=\"keepTitle\"> Keep &lt;title&gt; and &lt;desc&gt; for screen readers</label></div><div class=\"form-section\" x-show=\"mode === 'rasterize'\" style=\"display: none;\"><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Format</label> <select x-model=\"format\" class=\"form-input\"><option value=\"png\">PNG</option> <option value=\"webp\">WebP</option></select></div><div x-show=\"format === 'webp'\"><label class=\"sub-label\">Quality (%)</label> <input type=\"number\" x-model.number=\"quality\" min=\"1\" max=\"100\" class=\"form-input\"></div></div><label class=\"sub-label\" style=\"margin-top: 1rem;\">Widths (px)</label> <input type=\"text\" x-model=\"widths\" placeholder=\"Natural size\" class=\"form-input\"><p class=\"help-text\">Comma separated, e.g. 64, 128, 512. Heights follow the SVG's aspect ratio.</p></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"processing\"><span x-show=\"!processing\" x-text=\"{ optimize: 'Optimize SVG', sanitize: 'Sanitize SVG', rasterize: 'Render Images' }[mode]\">Optimize SVG</span> <span x-show=\"processing\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Processing...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!result && !rasters\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15.232 5.232l3.536 3.536m-2.036-5.036a2.5 2.5 0 113.536 3.536L6.5 21.036H3v-3.572L16.732 3.732z\"></path></svg><p>Your SVG will appear here</p></div><div x-show=\"result\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\" x-text=\"result && result.size < result.original_size ? '✓ ' + savings() + '% smaller' : '✓ Done'\"></span></div><div class=\"image-preview\"><img :src=\"result ? 'data:image/svg+xml;base64,' + btoa(unescape(encodeURIComponent(result.svg))) : ''\" alt=\"SVG preview\"></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Original:</span> <span class=\"meta-value\" x-text=\"result ? formatBytes(result.original_size) : ''\"></span></div><div class=\"meta-item\"><span class=\"meta-label\">Result:</span> <span class=\"meta-value\" x-text=\"result ? formatBytes(result.size) : ''\"></span></div><div class=\"meta-item\" x-show=\"result?.removed.length\"><span class=\"meta-label\">Removed:</span> <span class=\"meta-value\" x-text=\"result?.removed.join(', ')\"></span></div></div><div class=\"form-section\"><textarea class=\"json-textarea\" rows=\"8\" readonly x-text=\"result?.svg\"></textarea> <button @click=\"copy\" class=\"btn btn-secondary\">Copy markup</button></div><button @click=\"downloadSVG\" class=\"btn btn-primary btn-full\">Download SVG</button></div><div x-show=\"rasters\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ <span x-text=\"rasters?.files.length\"></span> images rendered</span></div><div class=\"image-preview\"><img :src=\"rasters ? 'data:' + (format === 'webp' ? 'image/webp' : 'image/png') + ';base64,' + rasters.preview : ''\" alt=\"Rendered SVG\"></div><div class=\"result-meta\"><template x-for=\"f in rasters?.files || []\" :key=\"f.name\"><div class=\"meta-item\"><span class=\"meta-label\" x-text=\"f.name\"></span> <span class=\"meta-value\" x-text=\"f.width + ' × ' + f.height + ' · ' + formatBytes(f.size)\"></span></div></template></div><button @click=\"downloadRasters\" class=\"btn btn-primary btn-full\" x-text=\"rasters?.files.length > 1 ? 'Download Images (ZIP)' : 'Download Image'\">Download Image</button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🛡️</div><h4 class=\"info-box-title\">Always sanitized</h4></div><p>Every SVG is cleaned before anything else happens: scripts, event handlers, embedded HTML and links to external files are removed. Rendering uses a built-in renderer, so nothing is ever fetched.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("SVG Tools").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,60 @@
+// Code generated by templ - DO NOT EDIT.
+
+// templ: version: v0.3.960
+package tools
+
+//lint:file-ignore SA4006 This context is only used if a nested component is present.
+
+import "github.com/a-h/templ"
+import templruntime "github.com/a-h/templ/runtime"
+
+import "github.com/tmunongo/nanotools/web/templates"
+
+func VideoToGIFPage() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var1 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var1 == nil {
+			templ_7745c5c3_Var1 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var2 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+			if !templ_7745c5c3_IsBuffer {
+				defer func() {
+					templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err == nil {
+						templ_7745c5c3_Err = templ_7745c5c3_BufErr
+					}
+				}()
+			}
+			ctx = templ.InitializeContext(ctx)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"tool-page\" x-data=\"videoToGif()\"><div class=\"tool-header\"><div class=\"tool-icon\">🎞️</div><h2>Video to GIF</h2><p class=\"tool-description\">Turn a short clip of a video into a high-quality GIF or animated WebP.</p></div><div class=\"tool-content\"><div class=\"tool-form\"><form @submit.prevent=\"convert\" enctype=\"multipart/form-data\"><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Source</label><div class=\"format-options\"><label class=\"format-option\"><input type=\"radio\" name=\"source\" value=\"upload\" x-model=\"source\"> <span class=\"format-card\"><span class=\"format-name\">Upload</span> <span class=\"format-desc\">A video file</span></span></label> <label class=\"format-option\"><input type=\"radio\" name=\"source\" value=\"url\" x-model=\"source\"> <span class=\"format-card\"><span class=\"format-name\">Link</span> <span class=\"format-desc\">Download from a site</span></span></label></div></div><div class=\"form-section\" x-show=\"source === 'upload'\"><input type=\"file\" @change=\"handleFileSelect\" accept=\"video/*\" class=\"file-input\"><p class=\"help-text\" x-show=\"file\" x-text=\"file ? file.name + ' · ' + formatBytes(file.size) : ''\"></p><p class=\"help-text\">MP4, WebM, MOV and most other formats (max 50MB)</p></div><div class=\"form-section\" x-show=\"source === 'url'\" style=\"display: none;\"><input type=\"url\" x-model=\"url\" placeholder=\"https://...\" class=\"form-input\"><p class=\"help-text\">YouTube links are currently not supported</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Clip</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Start</label> <input type=\"text\" x-model=\"start\" placeholder=\"0:00\" class=\"form-input\"></div><div><label class=\"sub-label\">End</label> <input type=\"text\" x-model=\"end\" placeholder=\"0:05\" class=\"form-input\"></div></div><p class=\"help-text\">Seconds or mm:ss. Clips are limited to 30 seconds.</p></div><div class=\"form-section\"><label class=\"form-label\"><span class=\"label-dot\"></span> Output</label><div class=\"settings-grid\" style=\"display: grid; gap: 1rem; grid-template-columns: 1fr 1fr;\"><div><label class=\"sub-label\">Format</label> <select x-model=\"format\" class=\"form-input\"><option value=\"gif\">GIF</option> <option value=\"webp\">Animated WebP</option></select></div><div><label class=\"sub-label\">Width (px)</label> <input type=\"number\" x-model.number=\"width\" min=\"16\" max=\"1280\" class=\"form-input\"></div><div><label class=\"sub-label\">Frame rate</label> <input type=\"number\" x-model.number=\"fps\" min=\"1\" max=\"30\" class=\"form-input\"></div><div><label class=\"sub-label\">Loop</label> <select x-model=\"loop\" class=\"form-input\"><option value=\"0\">Forever</option> <option value=\"-1\">Play once</option> <option value=\"2\">Three times</option></select></div><div x-show=\"format === 'webp'\"><label class=\"sub-label\">Quality (%)</label> <input type=\"number\" x-model.number=\"quality\" min=\"1\" max=\"100\" class=\"form-input\"></div></div><p class=\"help-text\">Smaller widths and lower frame rates give much smaller files</p></div><div class=\"form-actions\"><button type=\"submit\" class=\"btn btn-primary btn-full\" :disabled=\"converting\"><span x-show=\"!converting\">Create Animation</span> <span x-show=\"converting\" class=\"loading\" style=\"display: none;\"><span class=\"spinner\"></span> Converting...</span></button></div></form><div x-show=\"error\" class=\"error-message\" x-text=\"error\" style=\"display: none;\"></div></div><div class=\"output-section\"><div x-show=\"!resultUrl\" class=\"empty-state\"><svg class=\"empty-icon\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15 10l4.553-2.276A1 1 0 0121 8.618v6.764a1 1 0 01-1.447.894L15 14M5 18h8a2 2 0 002-2V8a2 2 0 00-2-2H5a2 2 0 00-2 2v8a2 2 0 002 2z\"></path></svg><p>Your animation will appear here</p></div><div x-show=\"resultUrl\" class=\"result-container\" style=\"display: none;\"><div class=\"output-header\"><span class=\"success-badge\">✓ Converted</span></div><div class=\"image-preview\"><img :src=\"resultUrl\" alt=\"Animation preview\"></div><div class=\"result-meta\"><div class=\"meta-item\"><span class=\"meta-label\">Size:</span> <span class=\"meta-value\" x-text=\"formatBytes(resultSize)\"></span></div></div><button @click=\"download\" class=\"btn btn-primary btn-full\">Download <span x-text=\"format === 'webp' ? 'WebP' : 'GIF'\"></span></button></div></div></div><div class=\"info-box info-box-info\"><div class=\"info-box-header\"><div class=\"info-box-icon\">🎨</div><h4 class=\"info-box-title\">Better colours</h4></div><p>GIFs can only show 256 colours. The video is analysed first to choose the best 256 colours for your clip, so gradients and skin tones look far better than with a fixed palette. Animated WebP has no such limit and is usually much smaller.</p></div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			return nil
+		})
+		templ_7745c5c3_Err = templates.Layout("Video to GIF").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+var _ = templruntime.GeneratedTemplate